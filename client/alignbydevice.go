@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AlignByDevice executes an ALIGN BY DEVICE query and returns its rows grouped
+// by device. The clause is appended to sql if it isn't there yet. The Device
+// column is consumed to group the rows, so the fields of every RowRecord only
+// hold the measurement columns; the typed accessors of the underlying data set
+// can still read it through DeviceColumnName.
+func (s *Session) AlignByDevice(sql string) (map[string][]*RowRecord, error) {
+	dataSet, err := s.executeQuery(alignByDeviceStatement(sql))
+	if err != nil {
+		return nil, err
+	}
+	defer dataSet.Close()
+	return groupRowsByDevice(dataSet)
+}
+
+// alignByDeviceStatement appends the ALIGN BY DEVICE clause to sql unless it
+// already has one outside of its quoted strings and names.
+func alignByDeviceStatement(sql string) string {
+	if clauseIndex(sqlWords(sql), []string{"align", "by", "device"}) >= 0 {
+		return sql
+	}
+	return strings.TrimRight(strings.TrimSpace(sql), ";") + " align by device"
+}
+
+func groupRowsByDevice(dataSet *SessionDataSet) (map[string][]*RowRecord, error) {
+	deviceIndex := -1
+	for i, name := range dataSet.GetColumnNames() {
+		if name == DeviceColumnName {
+			deviceIndex = i
+			break
+		}
+	}
+	if deviceIndex < 0 {
+		return nil, fmt.Errorf("the result has no %s column, is it an ALIGN BY DEVICE query", DeviceColumnName)
+	}
+
+	devices := make(map[string][]*RowRecord)
+	for {
+		hasNext, err := dataSet.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !hasNext {
+			break
+		}
+		record, err := dataSet.GetRowRecord()
+		if err != nil {
+			return nil, err
+		}
+		device := dataSet.GetText(DeviceColumnName)
+		fields := make([]*Field, 0, len(record.fields)-1)
+		fields = append(fields, record.fields[:deviceIndex]...)
+		fields = append(fields, record.fields[deviceIndex+1:]...)
+		record.fields = fields
+		devices[device] = append(devices[device], record)
+	}
+	return devices, nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func Test_alignByDeviceStatement(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "Without clause",
+			sql:  "select * from root.ln.*;",
+			want: "select * from root.ln.* align by device",
+		}, {
+			name: "With clause",
+			sql:  "select * from root.ln.* ALIGN BY DEVICE",
+			want: "select * from root.ln.* ALIGN BY DEVICE",
+		}, {
+			name: "Clause across lines",
+			sql:  "select * from root.ln.*\nalign by\tdevice",
+			want: "select * from root.ln.*\nalign by\tdevice",
+		}, {
+			name: "Quoted clause",
+			sql:  "select * from root.ln.* where status = 'align by device'",
+			want: "select * from root.ln.* where status = 'align by device' align by device",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alignByDeviceStatement(tt.sql); got != tt.want {
+				t.Errorf("alignByDeviceStatement() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_groupRowsByDevice(t *testing.T) {
+	time := append(int64ToBytes(1), append(int64ToBytes(2), int64ToBytes(3)...)...)
+	devices := append(append(int32ToBytes(15), []byte("root.ln.device1")...), append(int32ToBytes(15), []byte("root.ln.device2")...)...)
+	devices = append(devices, append(int32ToBytes(15), []byte("root.ln.device1")...)...)
+	counts := append(int32ToBytes(10), append(int32ToBytes(20), int32ToBytes(30)...)...)
+	queryDataSet := rpc.TSQueryDataSet{
+		Time:       time,
+		ValueList:  [][]byte{devices, counts},
+		BitmapList: [][]byte{{224}, {224}},
+	}
+	ds := NewSessionDataSet("select restart_count from root.ln.* align by device", []string{DeviceColumnName, "restart_count"},
+		[]string{"TEXT", "INT32"}, nil, 1, nil, 1, &queryDataSet, false, DefaultFetchSize)
+	ds.ioTDBRpcDataSet.emptyResultSet = true
+
+	got, err := groupRowsByDevice(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || len(got["root.ln.device1"]) != 2 || len(got["root.ln.device2"]) != 1 {
+		t.Fatalf("groupRowsByDevice() = %v", got)
+	}
+	record := got["root.ln.device1"][1]
+	if record.GetTimestamp() != 3 {
+		t.Errorf("groupRowsByDevice() timestamp = %d, want 3", record.GetTimestamp())
+	}
+	if fields := record.GetFields(); len(fields) != 1 || fields[0].GetName() != "restart_count" || fields[0].GetInt32() != 30 {
+		t.Errorf("groupRowsByDevice() fields = %v", fields)
+	}
+}
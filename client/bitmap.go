@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+// bitMap is a fixed size set of positions, one bit per position.
+type bitMap struct {
+	size int
+	bits []byte
+}
+
+func newBitMap(size int) *bitMap {
+	return &bitMap{
+		size: size,
+		bits: make([]byte, (size+7)/8),
+	}
+}
+
+func (b *bitMap) mark(position int) {
+	b.bits[position/8] |= 1 << uint(position%8)
+}
+
+func (b *bitMap) unmark(position int) {
+	b.bits[position/8] &^= 1 << uint(position%8)
+}
+
+func (b *bitMap) isMarked(position int) bool {
+	return b.bits[position/8]&(1<<uint(position%8)) != 0
+}
+
+// permute returns a bitmap whose position i is position index[i] of b.
+func (b *bitMap) permute(index []int) *bitMap {
+	permuted := newBitMap(b.size)
+	for i, from := range index {
+		if b.isMarked(from) {
+			permuted.mark(i)
+		}
+	}
+	return permuted
+}
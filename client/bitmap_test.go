@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import "testing"
+
+func Test_bitMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		marked    []int
+		unmarked  []int
+		wantBytes int
+	}{
+		{
+			name:      "One byte",
+			size:      8,
+			marked:    []int{0, 7},
+			unmarked:  []int{7},
+			wantBytes: 1,
+		}, {
+			name:      "Partial trailing byte",
+			size:      10,
+			marked:    []int{3, 9},
+			unmarked:  []int{},
+			wantBytes: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBitMap(tt.size)
+			if len(b.bits) != tt.wantBytes {
+				t.Errorf("newBitMap() = %d bytes, want %d", len(b.bits), tt.wantBytes)
+			}
+			want := make(map[int]bool)
+			for _, position := range tt.marked {
+				b.mark(position)
+				want[position] = true
+			}
+			for _, position := range tt.unmarked {
+				b.unmark(position)
+				delete(want, position)
+			}
+			for position := 0; position < tt.size; position++ {
+				if got := b.isMarked(position); got != want[position] {
+					t.Errorf("bitMap.isMarked(%d) = %v, want %v", position, got, want[position])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+const DefaultBreakerCooldown = 10 * time.Second
+
+// ErrCircuitOpen is returned without contacting the server while the circuit
+// breaker of the session is open, see Config.BreakerThreshold.
+var ErrCircuitOpen = errors.New("circuit breaker is open, the server is failing")
+
+// BreakerState is the state of the circuit breaker of a session.
+type BreakerState int
+
+const (
+	// BreakerClosed lets the calls through, it is the state of a session
+	// without breaker.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails the calls with ErrCircuitOpen until the cooldown ends.
+	BreakerOpen
+	// BreakerHalfOpen lets one trial call through, its success closes the
+	// breaker and its failure opens it again.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker counts the consecutive connection and RPC failures, the
+// errors reported in the status of a successful RPC aren't failures.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	logf      func(format string, v ...interface{})
+	clock     Clock
+
+	mutex    sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration, clock Clock, logf func(format string, v ...interface{})) *circuitBreaker {
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, clock: clock, logf: logf}
+}
+
+// allow reports whether a call may be attempted, after the cooldown it moves
+// the breaker to half-open and allows a single trial call.
+func (b *circuitBreaker) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if b.clock.Now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record updates the breaker with the outcome of an allowed call.
+func (b *circuitBreaker) record(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.probing = false
+	if err == nil {
+		b.setState(BreakerClosed)
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.clock.Now()
+		b.setState(BreakerOpen)
+	}
+}
+
+func (b *circuitBreaker) getState() BreakerState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) setState(state BreakerState) {
+	if b.state != state {
+		b.logf("circuit breaker %s, %d consecutive failures", state, b.failures)
+		b.state = state
+	}
+}
+
+// breakerClient guards the RPCs of a session with its circuit breaker.
+type breakerClient struct {
+	client  thrift.TClient
+	breaker *circuitBreaker
+}
+
+func (c *breakerClient) Call(ctx context.Context, method string, args, result thrift.TStruct) error {
+	if !c.breaker.allow() {
+		return ErrCircuitOpen
+	}
+	err := c.client.Call(ctx, method, args, result)
+	c.breaker.record(err)
+	return err
+}
+
+// BreakerState returns the state of the circuit breaker of the session, it is
+// always BreakerClosed when Config.BreakerThreshold is 0.
+func (s *Session) BreakerState() BreakerState {
+	if s.breaker == nil {
+		return BreakerClosed
+	}
+	return s.breaker.getState()
+}
@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	logger := &recordingLogger{}
+	clock := NewManualClock(time.Unix(0, 0))
+	breaker := newCircuitBreaker(2, time.Second, clock, logger.Printf)
+	failure := errors.New("connection refused")
+
+	steps := []struct {
+		name      string
+		wantAllow bool
+		err       error
+		advance   time.Duration
+		wantState BreakerState
+	}{
+		{name: "First failure", wantAllow: true, err: failure, wantState: BreakerClosed},
+		{name: "Threshold", wantAllow: true, err: failure, wantState: BreakerOpen},
+		{name: "Open", wantAllow: false, advance: time.Second, wantState: BreakerOpen},
+		{name: "Failed trial", wantAllow: true, err: failure, wantState: BreakerOpen},
+		{name: "Open again", wantAllow: false, advance: time.Second, wantState: BreakerOpen},
+		{name: "Successful trial", wantAllow: true, err: nil, wantState: BreakerClosed},
+		{name: "Closed", wantAllow: true, err: failure, wantState: BreakerClosed},
+	}
+	for _, step := range steps {
+		allowed := breaker.allow()
+		if allowed != step.wantAllow {
+			t.Fatalf("%s: circuitBreaker.allow() = %v, want %v", step.name, allowed, step.wantAllow)
+		}
+		if allowed {
+			if breaker.getState() == BreakerHalfOpen && breaker.allow() {
+				t.Fatalf("%s: circuitBreaker.allow() allowed a second trial", step.name)
+			}
+			breaker.record(step.err)
+		}
+		clock.Advance(step.advance)
+		if got := breaker.getState(); got != step.wantState {
+			t.Errorf("%s: circuitBreaker state = %v, want %v", step.name, got, step.wantState)
+		}
+	}
+	if len(logger.messages) == 0 {
+		t.Error("circuitBreaker didn't log its state changes")
+	}
+}
+
+func TestBreakerClient(t *testing.T) {
+	failure := errors.New("connection reset by peer")
+	breaker := newCircuitBreaker(1, time.Minute, realClock{}, (&recordingLogger{}).Printf)
+	failing := &failingClient{err: failure}
+	client := rpc.NewTSIServiceClient(&breakerClient{client: failing, breaker: breaker})
+
+	if _, err := client.GetTimeZone(context.Background(), 1); err != failure {
+		t.Errorf("GetTimeZone() error = %v, want %v", err, failure)
+	}
+	if _, err := client.GetTimeZone(context.Background(), 1); err != ErrCircuitOpen {
+		t.Errorf("GetTimeZone() error = %v, want %v", err, ErrCircuitOpen)
+	}
+	s := &Session{breaker: breaker}
+	if got := s.BreakerState(); got != BreakerOpen {
+		t.Errorf("Session.BreakerState() = %v, want %v", got, BreakerOpen)
+	}
+	if got := (&Session{}).BreakerState(); got != BreakerClosed {
+		t.Errorf("Session.BreakerState() without breaker = %v, want %v", got, BreakerClosed)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/iotdb-client-go/rpc"
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// CapturedInsert is an insert request sent by the session, recorded when
+// Config.CaptureInserts is set.
+type CapturedInsert struct {
+	Time time.Time
+	// Method is the RPC, such as insertTablet.
+	Method string
+	// Request is the request as it was sent, with its serialized values: a
+	// *rpc.TSInsertTabletReq, *rpc.TSInsertTabletsReq, *rpc.TSInsertRecordReq,
+	// *rpc.TSInsertRecordsReq, *rpc.TSInsertRecordsOfOneDeviceReq,
+	// *rpc.TSInsertStringRecordReq or *rpc.TSInsertStringRecordsReq. It can be
+	// dumped with a thrift.TSerializer and sent again with ReplayInsert.
+	Request thrift.TStruct
+	// Status and Err are the result of the RPC.
+	Status *rpc.TSStatus
+	Err    error
+}
+
+// LastInserts returns the last Config.CaptureInserts insert requests of the
+// session, the oldest first, nil if the capture is disabled.
+func (s *Session) LastInserts() []CapturedInsert {
+	if s.capture == nil {
+		return nil
+	}
+	return s.capture.list()
+}
+
+// ReplayInsert sends a captured insert request again, through this session,
+// for instance to reproduce a failure against a test server.
+func (s *Session) ReplayInsert(insert CapturedInsert) (r *rpc.TSStatus, err error) {
+	ctx := context.Background()
+	switch req := insert.Request.(type) {
+	case *rpc.TSInsertTabletReq:
+		replayed := *req
+		replayed.SessionId = s.sessionId
+		return s.client.InsertTablet(ctx, &replayed)
+	case *rpc.TSInsertTabletsReq:
+		replayed := *req
+		replayed.SessionId = s.sessionId
+		return s.client.InsertTablets(ctx, &replayed)
+	case *rpc.TSInsertRecordReq:
+		replayed := *req
+		replayed.SessionId = s.sessionId
+		return s.client.InsertRecord(ctx, &replayed)
+	case *rpc.TSInsertRecordsReq:
+		replayed := *req
+		replayed.SessionId = s.sessionId
+		return s.client.InsertRecords(ctx, &replayed)
+	case *rpc.TSInsertRecordsOfOneDeviceReq:
+		replayed := *req
+		replayed.SessionId = s.sessionId
+		return s.client.InsertRecordsOfOneDevice(ctx, &replayed)
+	case *rpc.TSInsertStringRecordReq:
+		replayed := *req
+		replayed.SessionId = s.sessionId
+		return s.client.InsertStringRecord(ctx, &replayed)
+	case *rpc.TSInsertStringRecordsReq:
+		replayed := *req
+		replayed.SessionId = s.sessionId
+		return s.client.InsertStringRecords(ctx, &replayed)
+	default:
+		return nil, fmt.Errorf("can't replay a %T request", insert.Request)
+	}
+}
+
+// insertCapture is a ring buffer of the last inserts.
+type insertCapture struct {
+	mutex   sync.Mutex
+	inserts []CapturedInsert
+	// next is the position of the next insert, the oldest one once the
+	// buffer is full.
+	next int
+	full bool
+}
+
+func newInsertCapture(size int) *insertCapture {
+	return &insertCapture{inserts: make([]CapturedInsert, size)}
+}
+
+func (c *insertCapture) add(insert CapturedInsert) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.inserts[c.next] = insert
+	c.next = (c.next + 1) % len(c.inserts)
+	if c.next == 0 {
+		c.full = true
+	}
+}
+
+func (c *insertCapture) list() []CapturedInsert {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.full {
+		return append([]CapturedInsert(nil), c.inserts[:c.next]...)
+	}
+	inserts := make([]CapturedInsert, 0, len(c.inserts))
+	inserts = append(inserts, c.inserts[c.next:]...)
+	return append(inserts, c.inserts[:c.next]...)
+}
+
+// captureClient records the insert requests in a capture.
+type captureClient struct {
+	client  thrift.TClient
+	capture *insertCapture
+	clock   Clock
+}
+
+func (c *captureClient) Call(ctx context.Context, method string, args, result thrift.TStruct) error {
+	request := insertRequest(args)
+	if request == nil {
+		return c.client.Call(ctx, method, args, result)
+	}
+	insert := CapturedInsert{Time: c.clock.Now(), Method: method, Request: request}
+	insert.Err = c.client.Call(ctx, method, args, result)
+	if statusResult, ok := result.(interface{ GetSuccess() *rpc.TSStatus }); ok && insert.Err == nil {
+		insert.Status = statusResult.GetSuccess()
+	}
+	c.capture.add(insert)
+	return insert.Err
+}
+
+// insertRequest returns the request of the arguments of an insert RPC, nil
+// for the other RPCs.
+func insertRequest(args thrift.TStruct) thrift.TStruct {
+	switch a := args.(type) {
+	case *rpc.TSIServiceInsertTabletArgs:
+		return a.Req
+	case *rpc.TSIServiceInsertTabletsArgs:
+		return a.Req
+	case *rpc.TSIServiceInsertRecordArgs:
+		return a.Req
+	case *rpc.TSIServiceInsertRecordsArgs:
+		return a.Req
+	case *rpc.TSIServiceInsertRecordsOfOneDeviceArgs:
+		return a.Req
+	case *rpc.TSIServiceInsertStringRecordArgs:
+		return a.Req
+	case *rpc.TSIServiceInsertStringRecordsArgs:
+		return a.Req
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func TestSession_LastInserts(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	capture := newInsertCapture(2)
+	status := &rpc.TSStatus{Code: SuccessStatus}
+	client := rpc.NewTSIServiceClient(&captureClient{client: &statusClient{status: status}, capture: capture, clock: clock})
+	s := &Session{config: &Config{}, client: client, sessionId: 1, capture: capture}
+	if got := (&Session{}).LastInserts(); got != nil {
+		t.Errorf("Session.LastInserts() without capture = %v", got)
+	}
+
+	for _, deviceId := range []string{"root.sg.d1", "root.sg.d2", "root.sg.d3"} {
+		clock.Advance(time.Second)
+		if _, err := client.InsertTablet(context.Background(), &rpc.TSInsertTabletReq{SessionId: 1, DeviceId: deviceId}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.GetTimeZone(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	inserts := s.LastInserts()
+	if len(inserts) != 2 {
+		t.Fatalf("Session.LastInserts() = %v, want 2 inserts", inserts)
+	}
+	for i, deviceId := range []string{"root.sg.d2", "root.sg.d3"} {
+		insert := inserts[i]
+		request, ok := insert.Request.(*rpc.TSInsertTabletReq)
+		if !ok || request.DeviceId != deviceId || insert.Method != "insertTablet" || insert.Status != status || insert.Err != nil {
+			t.Errorf("Session.LastInserts()[%d] = %+v, want the insert of %s", i, insert, deviceId)
+		}
+		if want := time.Unix(int64(i+2), 0); !insert.Time.Equal(want) {
+			t.Errorf("Session.LastInserts()[%d].Time = %v, want %v", i, insert.Time, want)
+		}
+	}
+
+	replayCapture := newInsertCapture(1)
+	replaySession := &Session{
+		client:    rpc.NewTSIServiceClient(&captureClient{client: &statusClient{status: status}, capture: replayCapture, clock: clock}),
+		sessionId: 9,
+	}
+	if _, err := replaySession.ReplayInsert(inserts[0]); err != nil {
+		t.Fatal(err)
+	}
+	replayed := replayCapture.list()[0].Request.(*rpc.TSInsertTabletReq)
+	if replayed.SessionId != 9 || replayed.DeviceId != "root.sg.d2" {
+		t.Errorf("Session.ReplayInsert() sent %+v", replayed)
+	}
+	if inserts[0].Request.(*rpc.TSInsertTabletReq).SessionId != 1 {
+		t.Error("Session.ReplayInsert() changed the captured request")
+	}
+	if _, err := replaySession.ReplayInsert(CapturedInsert{}); err == nil {
+		t.Error("Session.ReplayInsert() accepted an empty capture")
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// Checksum returns an integrity hash of the timestamps and values of the
+// tablet, to be recorded by audit pipelines and compared with the hash of the
+// same data read back. The device id and the measurement names are not part
+// of it.
+//
+// The hash is the 64-bit FNV-1a of the following bytes, in big-endian order,
+// and won't change across releases:
+//   - the timestamps of the rows, as int64, in row order;
+//   - for each column, in order, for each row, a 0 byte if the cell is unset,
+//     otherwise a 1 byte followed by the value: BOOLEAN as one byte, INT32,
+//     INT64, FLOAT and DOUBLE as their 4 or 8 bytes (IEEE 754 for floating
+//     point), TEXT as its length in bytes as int32 followed by its UTF-8 bytes.
+//
+// The rows are hashed in their order, so Sort the tablet before comparing it
+// with data read back in time order.
+func (t *Tablet) Checksum() uint64 {
+	hash := fnv.New64a()
+	binary.Write(hash, binary.BigEndian, t.timestamps[:t.rowCount])
+	for columnIndex := range t.measurementSchemas {
+		for row := 0; row < t.rowCount; row++ {
+			if !t.isSet(columnIndex, row) {
+				hash.Write([]byte{0})
+				continue
+			}
+			hash.Write([]byte{1})
+			value, _ := t.GetValueAt(columnIndex, row)
+			if text, ok := value.(string); ok {
+				binary.Write(hash, binary.BigEndian, int32(len(text)))
+				hash.Write([]byte(text))
+			} else {
+				binary.Write(hash, binary.BigEndian, value)
+			}
+		}
+	}
+	return hash.Sum64()
+}
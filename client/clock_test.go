@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Unix(100, 0)
+	clock := NewManualClock(start)
+	short := clock.After(time.Second)
+	long := clock.After(time.Minute)
+	select {
+	case <-clock.After(0):
+	default:
+		t.Error("ManualClock.After(0) didn't fire")
+	}
+
+	clock.Advance(time.Second)
+	if got := clock.Now(); !got.Equal(start.Add(time.Second)) {
+		t.Errorf("ManualClock.Now() = %v, want %v", got, start.Add(time.Second))
+	}
+	select {
+	case got := <-short:
+		if !got.Equal(start.Add(time.Second)) {
+			t.Errorf("ManualClock.After(1s) fired at %v", got)
+		}
+	default:
+		t.Error("ManualClock.After(1s) didn't fire after 1s")
+	}
+	select {
+	case <-long:
+		t.Error("ManualClock.After(1m) fired after 1s")
+	default:
+	}
+
+	clock.Advance(time.Hour)
+	select {
+	case <-long:
+	default:
+		t.Error("ManualClock.After(1m) didn't fire after 1h")
+	}
+}
+
+func TestTabletCoalescer_flushInterval(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := &Session{config: &Config{Clock: clock}}
+	c := NewTabletCoalescer(s, CoalescerConfig{FlushInterval: time.Minute})
+	defer close(c.stop)
+	for i := 0; i < 100; i++ {
+		clock.mutex.Lock()
+		waiting := len(clock.waiters)
+		clock.mutex.Unlock()
+		if waiting == 1 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("TabletCoalescer doesn't wait for its flush interval on the session clock")
+}
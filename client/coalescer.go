@@ -0,0 +1,354 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+const (
+	DefaultCoalesceInterval = time.Second
+	DefaultCoalesceMaxRows  = 10000
+)
+
+// ErrCoalescerClosed is returned by Add once the coalescer is closed.
+var ErrCoalescerClosed = errors.New("the tablet coalescer is closed")
+
+// CoalescerConfig tunes a TabletCoalescer.
+type CoalescerConfig struct {
+	// FlushInterval is the longest time a tablet is buffered before it is
+	// inserted, DefaultCoalesceInterval if it is 0.
+	FlushInterval time.Duration
+	// MaxRows triggers a flush once the buffered tablets hold that many rows,
+	// DefaultCoalesceMaxRows if it is 0.
+	MaxRows int
+	// ErrorHandler receives the tablets of a background flush which failed,
+	// the error is logged through the session Logger if it is nil. A panic
+	// of the flush is recovered and reported as a *PanicError.
+	ErrorHandler func(tablets []*Tablet, err error)
+	// StorageGroupDepth groups the buffered tablets by the first
+	// StorageGroupDepth segments of their device, the storage group when it is
+	// its depth, for a better write locality on the server. Each group is
+	// inserted with its own InsertTablets call, and flushed on its own when it
+	// reaches MaxRows. All the tablets form one group if it is 0.
+	StorageGroupDepth int
+}
+
+// CoalescerStats counts the inserts of a group of a TabletCoalescer.
+type CoalescerStats struct {
+	// Flushes is the number of InsertTablets calls, Failures the number of
+	// those which failed.
+	Flushes  int64
+	Failures int64
+	// Tablets and Rows count the coalesced tablets and rows inserted
+	// successfully.
+	Tablets int64
+	Rows    int64
+}
+
+// TabletCoalescer buffers small tablets and inserts them with fewer, larger
+// InsertTablets calls. Tablets of the same device and measurement schemas are
+// appended into one tablet, all the buffered tablets are sent together when the
+// flush interval elapses or the buffered rows reach MaxRows. With a
+// StorageGroupDepth they are sent per storage group instead.
+//
+// The inserts of the flush goroutine, Add, Flush and Close are serialized, the
+// session isn't safe for concurrent use so it must be dedicated to the
+// coalescer.
+type TabletCoalescer struct {
+	session *Session
+	config  CoalescerConfig
+	clock   Clock
+
+	mutex  sync.Mutex
+	groups map[string]*coalesceGroup
+	order  []string
+	stats  map[string]*CoalescerStats
+
+	// insertMutex serializes the inserts through the session, and guards
+	// closed so no tablet is buffered after the flush of Close.
+	insertMutex sync.Mutex
+	closed      bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// coalesceGroup holds the buffered tablets of a storage group.
+type coalesceGroup struct {
+	pending map[string]*Tablet
+	order   []string
+	rows    int
+}
+
+// NewTabletCoalescer creates a coalescer inserting through session and starts
+// its flush goroutine, it must be closed with Close.
+func NewTabletCoalescer(session *Session, config CoalescerConfig) *TabletCoalescer {
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultCoalesceInterval
+	}
+	if config.MaxRows <= 0 {
+		config.MaxRows = DefaultCoalesceMaxRows
+	}
+	c := &TabletCoalescer{
+		session: session,
+		config:  config,
+		clock:   session.clock(),
+		groups:  make(map[string]*coalesceGroup),
+		stats:   make(map[string]*CoalescerStats),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+func (c *TabletCoalescer) run() {
+	defer close(c.done)
+	for {
+		select {
+		case <-c.clock.After(c.config.FlushInterval):
+			c.flushInBackground()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *TabletCoalescer) flushInBackground() {
+	for _, group := range c.takeAll() {
+		c.flushGroup(group)
+	}
+}
+
+// flushGroup inserts a group from the flush goroutine, a panic is recovered
+// and handled as a failed insert so the goroutine keeps running.
+func (c *TabletCoalescer) flushGroup(group namedTablets) {
+	defer catchPanic(func(err *PanicError) {
+		c.handleError(group.tablets, err)
+	})
+	if _, err := c.insert(group.name, group.tablets); err != nil {
+		c.handleError(group.tablets, err)
+	}
+}
+
+func (c *TabletCoalescer) handleError(tablets []*Tablet, err error) {
+	if c.config.ErrorHandler != nil {
+		c.config.ErrorHandler(tablets, err)
+	} else {
+		c.session.logf("failed to insert %d coalesced tablets: %v", len(tablets), err)
+	}
+}
+
+// Add buffers a copy of tablet. When the buffered rows, of its storage group
+// with a StorageGroupDepth, reach MaxRows they are inserted right away and the
+// result is returned, otherwise the returned status is nil. It fails with
+// ErrCoalescerClosed once the coalescer is closed.
+func (c *TabletCoalescer) Add(tablet *Tablet) (r *rpc.TSStatus, err error) {
+	if err := tablet.Validate(); err != nil {
+		return nil, err
+	}
+	name, full, err := c.buffer(tablet)
+	if err != nil {
+		return nil, err
+	}
+	if full {
+		if tablets := c.take(name); len(tablets) > 0 {
+			return c.insert(name, tablets)
+		}
+	}
+	return nil, nil
+}
+
+// buffer appends a copy of tablet to its group, it returns the group and
+// whether it reached MaxRows.
+func (c *TabletCoalescer) buffer(tablet *Tablet) (name string, full bool, err error) {
+	c.insertMutex.Lock()
+	defer c.insertMutex.Unlock()
+	if c.closed {
+		return "", false, ErrCoalescerClosed
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	name = c.groupName(tablet.deviceId)
+	group, exists := c.groups[name]
+	if !exists {
+		group = &coalesceGroup{pending: make(map[string]*Tablet)}
+		c.groups[name] = group
+		c.order = append(c.order, name)
+	}
+	key := coalesceKey(tablet)
+	buffered, exists := group.pending[key]
+	if !exists {
+		buffered, err = NewTablet(tablet.deviceId, tablet.measurementSchemas, 0)
+		if err != nil {
+			return "", false, err
+		}
+		group.pending[key] = buffered
+		group.order = append(group.order, key)
+	}
+	if err = buffered.Append(tablet); err != nil {
+		return "", false, err
+	}
+	group.rows += tablet.rowCount
+	return name, group.rows >= c.config.MaxRows, nil
+}
+
+// Flush inserts the buffered tablets now. With a StorageGroupDepth every group
+// is inserted even if one fails, the status and the error of the first failure
+// are returned.
+func (c *TabletCoalescer) Flush() (r *rpc.TSStatus, err error) {
+	for _, group := range c.takeAll() {
+		status, insertErr := c.insert(group.name, group.tablets)
+		if err == nil {
+			r, err = status, insertErr
+		}
+	}
+	return r, err
+}
+
+// Close stops the flush goroutine and inserts the remaining tablets, closing
+// it again only flushes. Add fails once it is called.
+func (c *TabletCoalescer) Close() (r *rpc.TSStatus, err error) {
+	c.insertMutex.Lock()
+	c.closed = true
+	c.insertMutex.Unlock()
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	<-c.done
+	return c.Flush()
+}
+
+// Stats returns the insert counters of each group, by storage group with a
+// StorageGroupDepth, under the empty name otherwise.
+func (c *TabletCoalescer) Stats() map[string]CoalescerStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	stats := make(map[string]CoalescerStats, len(c.stats))
+	for name, s := range c.stats {
+		stats[name] = *s
+	}
+	return stats
+}
+
+// groupName returns the group of a device, the first StorageGroupDepth
+// segments of its path.
+func (c *TabletCoalescer) groupName(deviceId string) string {
+	if c.config.StorageGroupDepth <= 0 {
+		return ""
+	}
+	segments := SplitPath(deviceId)
+	if len(segments) > c.config.StorageGroupDepth {
+		segments = segments[:c.config.StorageGroupDepth]
+	}
+	return BuildPath(segments...)
+}
+
+type namedTablets struct {
+	name    string
+	tablets []*Tablet
+}
+
+// takeAll removes the buffered tablets of every group, in the order the
+// groups were created.
+func (c *TabletCoalescer) takeAll() []namedTablets {
+	c.mutex.Lock()
+	order := c.order
+	c.mutex.Unlock()
+	groups := make([]namedTablets, 0, len(order))
+	for _, name := range order {
+		if tablets := c.take(name); len(tablets) > 0 {
+			groups = append(groups, namedTablets{name: name, tablets: tablets})
+		}
+	}
+	return groups
+}
+
+// take removes the buffered tablets of a group.
+func (c *TabletCoalescer) take(name string) []*Tablet {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	group, exists := c.groups[name]
+	if !exists {
+		return nil
+	}
+	tablets := make([]*Tablet, len(group.order))
+	for i, key := range group.order {
+		tablets[i] = group.pending[key]
+	}
+	delete(c.groups, name)
+	for i, n := range c.order {
+		if n == name {
+			c.order = append(c.order[:i:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return tablets
+}
+
+func (c *TabletCoalescer) insert(name string, tablets []*Tablet) (r *rpc.TSStatus, err error) {
+	r, err = c.insertTablets(tablets)
+	if err == nil {
+		err = VerifySuccess(r)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	stats, exists := c.stats[name]
+	if !exists {
+		stats = &CoalescerStats{}
+		c.stats[name] = stats
+	}
+	stats.Flushes++
+	if err != nil {
+		stats.Failures++
+		return r, err
+	}
+	stats.Tablets += int64(len(tablets))
+	for _, tablet := range tablets {
+		stats.Rows += int64(tablet.rowCount)
+	}
+	return r, err
+}
+
+// insertTablets inserts through the session, one call at a time.
+func (c *TabletCoalescer) insertTablets(tablets []*Tablet) (*rpc.TSStatus, error) {
+	c.insertMutex.Lock()
+	defer c.insertMutex.Unlock()
+	return c.session.InsertTablets(tablets, false)
+}
+
+// coalesceKey identifies the tablets which can be appended to each other.
+func coalesceKey(tablet *Tablet) string {
+	key := strings.Builder{}
+	key.WriteString(tablet.deviceId)
+	for _, schema := range tablet.measurementSchemas {
+		fmt.Fprintf(&key, "|%s:%d", schema.Measurement, schema.DataType)
+	}
+	return key.String()
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func createCoalescer(storageGroupDepth int) *TabletCoalescer {
+	return &TabletCoalescer{
+		config:  CoalescerConfig{FlushInterval: time.Hour, MaxRows: DefaultCoalesceMaxRows, StorageGroupDepth: storageGroupDepth},
+		session: &Session{config: &Config{}},
+		groups:  make(map[string]*coalesceGroup),
+		stats:   make(map[string]*CoalescerStats),
+	}
+}
+
+func TestTabletCoalescer_take(t *testing.T) {
+	device1, _ := createTablet(2)
+	device1Again, _ := createTablet(3)
+	other, _ := NewTablet("root.ln.device2", []*MeasurementSchema{{Measurement: "temperature", DataType: FLOAT}}, 1)
+	other.SetValueAt(float32(36.5), 0, 0)
+
+	c := createCoalescer(0)
+	for _, tablet := range []*Tablet{device1, other, device1Again} {
+		if _, err := c.Add(tablet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c.groups[""].rows != 6 {
+		t.Errorf("TabletCoalescer.Add() rows = %d, want 6", c.groups[""].rows)
+	}
+	tablets := c.take("")
+	if len(tablets) != 2 {
+		t.Fatalf("TabletCoalescer.take() = %d tablets, want 2", len(tablets))
+	}
+	if tablets[0].deviceId != "root.ln.TestDevice" || tablets[0].GetRowCount() != 5 {
+		t.Errorf("TabletCoalescer.take() first tablet = %s with %d rows", tablets[0].deviceId, tablets[0].GetRowCount())
+	}
+	if tablets[1].deviceId != "root.ln.device2" || tablets[1].GetRowCount() != 1 {
+		t.Errorf("TabletCoalescer.take() second tablet = %s with %d rows", tablets[1].deviceId, tablets[1].GetRowCount())
+	}
+	if device1.GetRowCount() != 2 {
+		t.Errorf("TabletCoalescer.Add() modified the added tablet")
+	}
+	if len(c.take("")) != 0 || len(c.groups) != 0 {
+		t.Errorf("TabletCoalescer.take() didn't reset the buffer")
+	}
+}
+
+func TestTabletCoalescer_storageGroups(t *testing.T) {
+	newTablet := func(deviceId string) *Tablet {
+		tablet, _ := NewTablet(deviceId, []*MeasurementSchema{{Measurement: "temperature", DataType: FLOAT}}, 1)
+		tablet.SetValueAt(float32(36.5), 0, 0)
+		return tablet
+	}
+	c := createCoalescer(2)
+	for _, deviceId := range []string{"root.sg1.d1", "root.sg2.d1", "root.sg1.d2", "root"} {
+		if _, err := c.Add(newTablet(deviceId)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(c.order, []string{"root.sg1", "root.sg2", "root"}) {
+		t.Errorf("TabletCoalescer.Add() groups = %v", c.order)
+	}
+	groups := c.takeAll()
+	if len(groups) != 3 || groups[0].name != "root.sg1" || len(groups[0].tablets) != 2 || len(groups[1].tablets) != 1 {
+		t.Errorf("TabletCoalescer.takeAll() = %+v", groups)
+	}
+
+	c.session.client = rpc.NewTSIServiceClient(&failingClient{err: errors.New("connection reset by peer")})
+	c.Add(newTablet("root.sg1.d1"))
+	if _, err := c.Flush(); err == nil {
+		t.Error("TabletCoalescer.Flush() returned no error")
+	}
+	want := map[string]CoalescerStats{"root.sg1": {Flushes: 1, Failures: 1}}
+	if got := c.Stats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TabletCoalescer.Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTabletCoalescer_Close(t *testing.T) {
+	c := NewTabletCoalescer(&Session{config: &Config{}}, CoalescerConfig{FlushInterval: time.Hour})
+	for i := 0; i < 2; i++ {
+		if _, err := c.Close(); err != nil {
+			t.Errorf("TabletCoalescer.Close() #%d error = %v", i+1, err)
+		}
+	}
+}
+
+func TestTabletCoalescer_AddAfterClose(t *testing.T) {
+	c := NewTabletCoalescer(&Session{config: &Config{}}, CoalescerConfig{FlushInterval: time.Hour})
+	if _, err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tablet, _ := createTablet(2)
+	if _, err := c.Add(tablet); !errors.Is(err, ErrCoalescerClosed) {
+		t.Errorf("TabletCoalescer.Add() error = %v, want %v", err, ErrCoalescerClosed)
+	}
+	if len(c.groups) != 0 {
+		t.Errorf("TabletCoalescer.Add() buffered %d groups after Close", len(c.groups))
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrContinuousQueryUnsupported is returned by the continuous query methods
+// when the server predates them.
+var ErrContinuousQueryUnsupported = errors.New("the server doesn't support continuous queries, they require 0.12 or later")
+
+// The first server release with continuous queries.
+const (
+	cqMinMajorVersion = 0
+	cqMinMinorVersion = 12
+)
+
+// ContinuousQuery is a query the server runs periodically, writing its result
+// into TargetPath.
+type ContinuousQuery struct {
+	Name string
+	// Query is the SELECT statement, without its INTO clause, such as
+	// "select max_value(temperature) from root.ln.*.* group by time(10s)".
+	Query string
+	// ResampleInterval is the period of the query, the group by interval of
+	// the query when 0.
+	ResampleInterval time.Duration
+	// TargetPath is the INTO target of the query, a full path or one relative
+	// to the devices of the query. ${N} is replaced by the server with the
+	// Nth segment of the device of each result, such as
+	// root.ln.${2}.temperature_max.
+	TargetPath string
+}
+
+// cqPlaceholder matches the ${N} placeholders of a TargetPath.
+var cqPlaceholder = regexp.MustCompile(`\$\{[0-9]+\}`)
+
+// The columns of the SHOW CONTINUOUS QUERIES result.
+const (
+	cqNameColumnName       = "cq name"
+	cqEveryColumnName      = "every interval"
+	cqQueryColumnName      = "query sql"
+	cqTargetPathColumnName = "target path"
+)
+
+// CreateContinuousQuery creates a continuous query on the server.
+func (s *Session) CreateContinuousQuery(cq ContinuousQuery) error {
+	sql, err := createContinuousQueryStatement(cq)
+	if err != nil {
+		return err
+	}
+	if err := s.checkContinuousQueries(); err != nil {
+		return err
+	}
+	r, err := s.ExecuteNonQueryStatement(sql)
+	if err != nil {
+		return err
+	}
+	return VerifySuccess(r)
+}
+
+// DropContinuousQuery drops the continuous query with the name.
+func (s *Session) DropContinuousQuery(name string) error {
+	if err := ValidateIdentifier(name); err != nil {
+		return err
+	}
+	if err := s.checkContinuousQueries(); err != nil {
+		return err
+	}
+	r, err := s.ExecuteNonQueryStatement("drop continuous query " + name)
+	if err != nil {
+		return err
+	}
+	return VerifySuccess(r)
+}
+
+// ShowContinuousQueries returns the continuous queries of the server. The
+// query text is returned as the server prints it, with its INTO clause.
+func (s *Session) ShowContinuousQueries() ([]ContinuousQuery, error) {
+	if err := s.checkContinuousQueries(); err != nil {
+		return nil, err
+	}
+	dataSet, err := s.executeQuery("show continuous queries")
+	if err != nil {
+		return nil, err
+	}
+	defer dataSet.Close()
+	return readContinuousQueries(dataSet)
+}
+
+// checkContinuousQueries fails when the server version is known and predates
+// the continuous queries.
+func (s *Session) checkContinuousQueries() error {
+	return s.checkServerVersion(cqMinMajorVersion, cqMinMinorVersion, "continuous queries", ErrContinuousQueryUnsupported)
+}
+
+// checkServerVersion returns unsupported when the server version is known and
+// predates major.minor, the first release supporting feature.
+func (s *Session) checkServerVersion(major, minor int, feature string, unsupported error) error {
+	properties, err := s.client.GetProperties(context.Background())
+	if err != nil || properties == nil {
+		s.logf("can't get the version of the server, assuming it supports %s: %v", feature, err)
+		return nil
+	}
+	serverMajor, serverMinor, err := parseServerVersion(properties.GetVersion())
+	if err != nil {
+		s.logf("%v, assuming it supports %s", err, feature)
+		return nil
+	}
+	if serverMajor < major || serverMajor == major && serverMinor < minor {
+		return fmt.Errorf("%w, the server is %s", unsupported, properties.GetVersion())
+	}
+	return nil
+}
+
+// parseServerVersion returns the major and minor numbers of a server version,
+// such as 0.12.4 or 0.13.0-SNAPSHOT.
+func parseServerVersion(version string) (major, minor int, err error) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("illegal server version %q", version)
+	}
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("illegal server version %q", version)
+	}
+	minorPart := parts[1]
+	if i := strings.IndexFunc(minorPart, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		minorPart = minorPart[:i]
+	}
+	if minor, err = strconv.Atoi(minorPart); err != nil {
+		return 0, 0, fmt.Errorf("illegal server version %q", version)
+	}
+	return major, minor, nil
+}
+
+func createContinuousQueryStatement(cq ContinuousQuery) (string, error) {
+	if err := ValidateIdentifier(cq.Name); err != nil {
+		return "", err
+	}
+	// the placeholders are validated as plain identifiers
+	targetPath := cqPlaceholder.ReplaceAllString(cq.TargetPath, "x")
+	if !strings.HasPrefix(targetPath, "root.") {
+		// a target relative to the devices of the query
+		targetPath = "root." + targetPath
+	}
+	if err := ValidatePath(targetPath); err != nil {
+		return "", err
+	}
+	query := strings.TrimSpace(cq.Query)
+	lower := strings.ToLower(query)
+	if !strings.HasPrefix(lower, "select ") {
+		return "", fmt.Errorf("the query of continuous query %s must be a SELECT statement", cq.Name)
+	}
+	if strings.Contains(lower, " into ") {
+		return "", fmt.Errorf("the query of continuous query %s can't have an INTO clause, set TargetPath", cq.Name)
+	}
+	from := strings.Index(lower, " from ")
+	if from < 0 {
+		return "", fmt.Errorf("the query of continuous query %s has no FROM clause", cq.Name)
+	}
+	sql := "create continuous query " + cq.Name
+	if cq.ResampleInterval != 0 {
+		interval, err := formatInterval(cq.ResampleInterval)
+		if err != nil {
+			return "", err
+		}
+		sql += " resample every " + interval
+	}
+	return sql + " begin " + query[:from] + " into " + cq.TargetPath + query[from:] + " end", nil
+}
+
+// The duration units of the statements, largest first.
+var intervalUnits = []struct {
+	unit    time.Duration
+	literal string
+}{
+	{24 * time.Hour, "d"},
+	{time.Hour, "h"},
+	{time.Minute, "m"},
+	{time.Second, "s"},
+	{time.Millisecond, "ms"},
+}
+
+// formatInterval writes a duration as a literal of the statements, such as
+// 20s, in its largest exact unit.
+func formatInterval(d time.Duration) (string, error) {
+	if d <= 0 || d%time.Millisecond != 0 {
+		return "", fmt.Errorf("illegal interval %v, it must be a positive number of milliseconds", d)
+	}
+	for _, u := range intervalUnits {
+		if d%u.unit == 0 {
+			return strconv.FormatInt(int64(d/u.unit), 10) + u.literal, nil
+		}
+	}
+	return "", fmt.Errorf("illegal interval %v", d)
+}
+
+// parseInterval reads an interval of the SHOW CONTINUOUS QUERIES result,
+// printed in milliseconds.
+func parseInterval(text string) (time.Duration, error) {
+	ms, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("illegal interval %q", text)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+func readContinuousQueries(dataSet *SessionDataSet) ([]ContinuousQuery, error) {
+	cqs := make([]ContinuousQuery, 0)
+	for {
+		hasNext, err := dataSet.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !hasNext {
+			return cqs, nil
+		}
+		cq := ContinuousQuery{
+			Name:       dataSet.GetText(cqNameColumnName),
+			Query:      dataSet.GetText(cqQueryColumnName),
+			TargetPath: dataSet.GetText(cqTargetPathColumnName),
+		}
+		if every := dataSet.GetText(cqEveryColumnName); every != "" {
+			if cq.ResampleInterval, err = parseInterval(every); err != nil {
+				return nil, err
+			}
+		}
+		cqs = append(cqs, cq)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_createContinuousQueryStatement(t *testing.T) {
+	tests := []struct {
+		name    string
+		cq      ContinuousQuery
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Resampled",
+			cq: ContinuousQuery{
+				Name:             "cq1",
+				Query:            "select max_value(temperature) from root.ln.*.* group by time(10s)",
+				ResampleInterval: 20 * time.Second,
+				TargetPath:       "temperature_max",
+			},
+			want: "create continuous query cq1 resample every 20s begin select max_value(temperature) into temperature_max from root.ln.*.* group by time(10s) end",
+		}, {
+			name: "Default interval",
+			cq: ContinuousQuery{
+				Name:       "cq2",
+				Query:      "SELECT avg(s1) FROM root.sg.d1 GROUP BY time(1m)",
+				TargetPath: "root.sg.d1.s1_avg",
+			},
+			want: "create continuous query cq2 begin SELECT avg(s1) into root.sg.d1.s1_avg FROM root.sg.d1 GROUP BY time(1m) end",
+		}, {
+			name: "Placeholder",
+			cq: ContinuousQuery{
+				Name:       "cq6",
+				Query:      "select max_value(temperature) from root.ln.*.* group by time(10s)",
+				TargetPath: "root.ln.${2}.${3}_max.temperature",
+			},
+			want: "create continuous query cq6 begin select max_value(temperature) into root.ln.${2}.${3}_max.temperature from root.ln.*.* group by time(10s) end",
+		}, {
+			name: "Illegal placeholder",
+			cq: ContinuousQuery{
+				Name:       "cq7",
+				Query:      "select max_value(temperature) from root.ln.*.* group by time(10s)",
+				TargetPath: "root.ln.${x}.temperature_max",
+			},
+			wantErr: true,
+		}, {
+			name: "Into clause",
+			cq: ContinuousQuery{
+				Name:       "cq3",
+				Query:      "select avg(s1) into s1_avg from root.sg.d1 group by time(1m)",
+				TargetPath: "s1_avg",
+			},
+			wantErr: true,
+		}, {
+			name: "Not a select",
+			cq: ContinuousQuery{
+				Name:       "cq4",
+				Query:      "delete timeseries root.**",
+				TargetPath: "s1_avg",
+			},
+			wantErr: true,
+		}, {
+			name: "Sub-millisecond interval",
+			cq: ContinuousQuery{
+				Name:             "cq5",
+				Query:            "select avg(s1) from root.sg.d1 group by time(1m)",
+				ResampleInterval: time.Microsecond,
+				TargetPath:       "s1_avg",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := createContinuousQueryStatement(tt.cq)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("createContinuousQueryStatement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("createContinuousQueryStatement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseServerVersion(t *testing.T) {
+	tests := []struct {
+		version   string
+		wantMajor int
+		wantMinor int
+		wantErr   bool
+	}{
+		{version: "0.12.4", wantMajor: 0, wantMinor: 12},
+		{version: "0.13-SNAPSHOT", wantMajor: 0, wantMinor: 13},
+		{version: "1.0.0", wantMajor: 1, wantMinor: 0},
+		{version: "UNKNOWN", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			major, minor, err := parseServerVersion(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseServerVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if major != tt.wantMajor || minor != tt.wantMinor {
+				t.Errorf("parseServerVersion() = %d.%d, want %d.%d", major, minor, tt.wantMajor, tt.wantMinor)
+			}
+		})
+	}
+}
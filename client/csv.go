@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSVOptions formats the output of SessionDataSet.WriteCSV.
+type CSVOptions struct {
+	// Delimiter separates the fields, a comma if it is 0.
+	Delimiter rune
+	// Null is written for null values, an empty field by default.
+	Null string
+	// TimeLayout formats the Time column with time.Time.Format, for example
+	// time.RFC3339, the raw int64 timestamps are written if it is empty.
+	TimeLayout string
+}
+
+// WriteCSV writes the remaining rows of the data set to w as CSV, after a
+// header of the column names. The rows are written as they are fetched, so
+// large results aren't held in memory. It doesn't close the data set.
+func (s *SessionDataSet) WriteCSV(w io.Writer, options CSVOptions) error {
+	writer := csv.NewWriter(w)
+	if options.Delimiter != 0 {
+		writer.Comma = options.Delimiter
+	}
+	withTime := !s.IsIgnoreTimeStamp()
+	header := make([]string, 0, s.GetColumnCount()+1)
+	if withTime {
+		header = append(header, TimestampColumnName)
+	}
+	header = append(header, s.GetColumnNames()...)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	line := make([]string, len(header))
+	for {
+		hasNext, err := s.Next()
+		if err != nil {
+			return err
+		}
+		if !hasNext {
+			break
+		}
+		record, err := s.GetRowRecord()
+		if err != nil {
+			return err
+		}
+		fields := line
+		if withTime {
+			if options.TimeLayout == "" {
+				line[0] = int64ToString(record.GetTimestamp())
+			} else {
+				line[0] = s.GetTime().Format(options.TimeLayout)
+			}
+			fields = line[1:]
+		}
+		for i, field := range record.GetFields() {
+			if field.IsNull() {
+				fields[i] = options.Null
+			} else {
+				fields[i] = field.GetText()
+			}
+		}
+		if err := writer.Write(line); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
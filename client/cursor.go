@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	errCursorClosed = errors.New("the cursor is closed")
+	errNoCurrentRow = errors.New("no current row, call Next first")
+)
+
+// Cursor iterates over the rows of a query result, for adapters to generic
+// tabular tooling. Its behavior is part of the contract:
+//
+//   - HasNext reports whether Next would move to a row, it doesn't move.
+//   - Next moves to the next row, it returns io.EOF after the last one.
+//   - Values returns the current row, it is an error before the first Next
+//     and after Next returned an error.
+//   - Every method but Close returns an error once the cursor is closed.
+//   - Close frees the query on the server, calling it again returns nil.
+//
+// A Cursor isn't safe for concurrent use.
+type Cursor interface {
+	HasNext() (bool, error)
+	Next() error
+	// Columns returns the column names, Time first unless the result has no
+	// timestamps.
+	Columns() []string
+	// Values returns the values of the current row in the order of Columns:
+	// the timestamp as an int64 then bool, int32, int64, float32, float64 or
+	// string values, nil for null ones.
+	Values() ([]interface{}, error)
+	Close() error
+}
+
+// Cursor returns a Cursor over the data set, which must not be used directly
+// anymore. Closing the cursor closes the data set.
+func (s *SessionDataSet) Cursor() Cursor {
+	return &dataSetCursor{dataSet: s}
+}
+
+type dataSetCursor struct {
+	dataSet *SessionDataSet
+	// peeked is set when HasNext moved the data set to the next row, which
+	// has, if it exists, not been read yet.
+	peeked  bool
+	hasNext bool
+	// values is the current row, read by Next as HasNext replaces the row of
+	// the data set.
+	values []interface{}
+	closed bool
+}
+
+func (c *dataSetCursor) HasNext() (bool, error) {
+	if c.closed {
+		return false, errCursorClosed
+	}
+	if !c.peeked {
+		hasNext, err := c.dataSet.Next()
+		if err != nil {
+			c.values = nil
+			return false, err
+		}
+		c.peeked = true
+		c.hasNext = hasNext
+	}
+	return c.hasNext, nil
+}
+
+func (c *dataSetCursor) Next() error {
+	hasNext, err := c.HasNext()
+	if err != nil {
+		return err
+	}
+	c.peeked = false
+	if !hasNext {
+		c.values = nil
+		return io.EOF
+	}
+	c.values, err = c.readRow()
+	return err
+}
+
+func (c *dataSetCursor) readRow() ([]interface{}, error) {
+	record, err := c.dataSet.GetRowRecord()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, 0, len(record.GetFields())+1)
+	if !c.dataSet.IsIgnoreTimeStamp() {
+		values = append(values, record.GetTimestamp())
+	}
+	for _, field := range record.GetFields() {
+		values = append(values, field.GetValue())
+	}
+	return values, nil
+}
+
+func (c *dataSetCursor) Columns() []string {
+	columns := make([]string, 0, c.dataSet.GetColumnCount()+1)
+	if !c.dataSet.IsIgnoreTimeStamp() {
+		columns = append(columns, TimestampColumnName)
+	}
+	return append(columns, c.dataSet.GetColumnNames()...)
+}
+
+func (c *dataSetCursor) Values() ([]interface{}, error) {
+	if c.closed {
+		return nil, errCursorClosed
+	}
+	if c.values == nil {
+		return nil, errNoCurrentRow
+	}
+	return c.values, nil
+}
+
+func (c *dataSetCursor) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.values = nil
+	return c.dataSet.Close()
+}
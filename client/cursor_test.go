@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSessionDataSet_Cursor(t *testing.T) {
+	cursor := createSessionDataSet().Cursor()
+	columns := cursor.Columns()
+	if len(columns) != 7 || columns[0] != TimestampColumnName || columns[1] != "root.ln.device1.restart_count" {
+		t.Errorf("Cursor.Columns() = %v", columns)
+	}
+	if _, err := cursor.Values(); err != errNoCurrentRow {
+		t.Errorf("Cursor.Values() before Next error = %v, want %v", err, errNoCurrentRow)
+	}
+
+	rows := 0
+	for {
+		hasNext, err := cursor.HasNext()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again, _ := cursor.HasNext(); again != hasNext {
+			t.Fatal("Cursor.HasNext() moved the cursor")
+		}
+		if err := cursor.Next(); err != nil {
+			if hasNext || err != io.EOF {
+				t.Fatalf("Cursor.Next() error = %v, HasNext() = %v", err, hasNext)
+			}
+			break
+		}
+		values, err := cursor.Values()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != len(columns) {
+			t.Fatalf("Cursor.Values() = %v, want %d values", values, len(columns))
+		}
+		if _, ok := values[0].(int64); !ok || values[1] != int32(1) || values[5] != "Test Device 1" {
+			t.Errorf("Cursor.Values() = %v", values)
+		}
+		rows++
+	}
+	if rows != 5 {
+		t.Errorf("Cursor read %d rows, want 5", rows)
+	}
+	if _, err := cursor.Values(); err != errNoCurrentRow {
+		t.Errorf("Cursor.Values() after the last row error = %v, want %v", err, errNoCurrentRow)
+	}
+
+	if err := cursor.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cursor.Close(); err != nil {
+		t.Errorf("second Cursor.Close() error = %v", err)
+	}
+	if _, err := cursor.HasNext(); err != errCursorClosed {
+		t.Errorf("Cursor.HasNext() after Close error = %v, want %v", err, errCursorClosed)
+	}
+	if err := cursor.Next(); err != errCursorClosed {
+		t.Errorf("Cursor.Next() after Close error = %v, want %v", err, errCursorClosed)
+	}
+	if _, err := cursor.Values(); err != errCursorClosed {
+		t.Errorf("Cursor.Values() after Close error = %v, want %v", err, errCursorClosed)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// DecodeTimestamps reads the timestamps of rowCount rows in the format of
+// Tablet.GetTimestampBytes.
+func DecodeTimestamps(timestampBytes []byte, rowCount int) ([]int64, error) {
+	if rowCount < 0 || rowCount > len(timestampBytes)/8 || len(timestampBytes) != 8*rowCount {
+		return nil, fmt.Errorf("%d timestamp bytes for %d rows, want %d", len(timestampBytes), rowCount, 8*rowCount)
+	}
+	timestamps := make([]int64, rowCount)
+	if err := binary.Read(bytes.NewReader(timestampBytes), binary.BigEndian, timestamps); err != nil {
+		return nil, err
+	}
+	return timestamps, nil
+}
+
+// DecodeValues reads the values of a tablet insert request, the inverse of
+// the tablet serialization: the columns in the order of the schemas, each a
+// []bool, []int32, []int64, []float32, []float64 or []string of rowCount
+// values.
+func DecodeValues(valueBytes []byte, schemas []*MeasurementSchema, rowCount int) ([]interface{}, error) {
+	if rowCount < 0 {
+		return nil, fmt.Errorf("illegal row count %d", rowCount)
+	}
+	reader := bytes.NewReader(valueBytes)
+	values := make([]interface{}, len(schemas))
+	for i, schema := range schemas {
+		// Check the remaining bytes before allocating the column, a forged row
+		// count must not allocate more than the buffer can hold.
+		if size := minValueSize(schema.DataType); size > 0 && rowCount > reader.Len()/size {
+			return nil, fmt.Errorf("can't decode column %s: %d bytes left for %d rows", schema.Measurement, reader.Len(), rowCount)
+		}
+		var err error
+		switch schema.DataType {
+		case BOOLEAN:
+			column := make([]bool, rowCount)
+			err = binary.Read(reader, binary.BigEndian, column)
+			values[i] = column
+		case INT32:
+			column := make([]int32, rowCount)
+			err = binary.Read(reader, binary.BigEndian, column)
+			values[i] = column
+		case INT64:
+			column := make([]int64, rowCount)
+			err = binary.Read(reader, binary.BigEndian, column)
+			values[i] = column
+		case FLOAT:
+			column := make([]float32, rowCount)
+			err = binary.Read(reader, binary.BigEndian, column)
+			values[i] = column
+		case DOUBLE:
+			column := make([]float64, rowCount)
+			err = binary.Read(reader, binary.BigEndian, column)
+			values[i] = column
+		case TEXT:
+			column := make([]string, rowCount)
+			for row := range column {
+				if column[row], err = readText(reader); err != nil {
+					break
+				}
+			}
+			values[i] = column
+		default:
+			return nil, fmt.Errorf("Illegal datatype %v of column %s", schema.DataType, schema.Measurement)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't decode column %s: %v", schema.Measurement, err)
+		}
+	}
+	if reader.Len() > 0 {
+		return nil, fmt.Errorf("%d trailing value bytes after %d rows", reader.Len(), rowCount)
+	}
+	return values, nil
+}
+
+// DecodeTablet builds a tablet from the buffers of a tablet insert request.
+// All its cells are considered set.
+func DecodeTablet(deviceId string, schemas []*MeasurementSchema, rowCount int, timestampBytes, valueBytes []byte) (*Tablet, error) {
+	timestamps, err := DecodeTimestamps(timestampBytes, rowCount)
+	if err != nil {
+		return nil, err
+	}
+	values, err := DecodeValues(valueBytes, schemas, rowCount)
+	if err != nil {
+		return nil, err
+	}
+	return &Tablet{
+		deviceId:           deviceId,
+		measurementSchemas: schemas,
+		timestamps:         timestamps,
+		values:             values,
+		rowCount:           rowCount,
+	}, nil
+}
+
+// minValueSize returns the smallest serialized size of a value of dataType,
+// the length prefix for TEXT, or 0 if the data type is illegal.
+func minValueSize(dataType TSDataType) int {
+	switch dataType {
+	case BOOLEAN:
+		return 1
+	case INT32, FLOAT, TEXT:
+		return 4
+	case INT64, DOUBLE:
+		return 8
+	default:
+		return 0
+	}
+}
+
+func readText(reader *bytes.Reader) (string, error) {
+	var length int32
+	if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	if length < 0 || int(length) > reader.Len() {
+		return "", fmt.Errorf("illegal text length %d", length)
+	}
+	text := make([]byte, length)
+	if _, err := reader.Read(text); err != nil && length > 0 {
+		return "", err
+	}
+	return string(text), nil
+}
@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeTablet_roundTrip(t *testing.T) {
+	schemas := []*MeasurementSchema{
+		{Measurement: "status", DataType: BOOLEAN},
+		{Measurement: "restart_count", DataType: INT32},
+		{Measurement: "tick_count", DataType: INT64},
+		{Measurement: "temperature", DataType: FLOAT},
+		{Measurement: "price", DataType: DOUBLE},
+		{Measurement: "description", DataType: TEXT},
+	}
+	tablet, err := NewTablet("root.ln.device1", schemas, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]interface{}{
+		{true, int32(1), int64(-1), float32(36.5), 0.1, "first"},
+		{false, int32(-2), int64(1) << 40, float32(-0.5), -1e300, ""},
+		{true, int32(3), int64(0), float32(1e-7), 42.0, "third, with unicode: é"},
+	}
+	for row, values := range rows {
+		tablet.SetTimestamp(int64(row)-1, row)
+		for column, value := range values {
+			if err := tablet.SetValueAt(value, column, row); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	timestampBytes := tablet.GetTimestampBytes()
+	valueBytes, err := tablet.getValuesBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodeTablet("root.ln.device1", schemas, 3, timestampBytes, valueBytes)
+	if err != nil {
+		t.Fatalf("DecodeTablet() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded.timestamps, tablet.timestamps) || !reflect.DeepEqual(decoded.values, tablet.values) {
+		t.Errorf("DecodeTablet() = %v %v, want %v %v", decoded.timestamps, decoded.values, tablet.timestamps, tablet.values)
+	}
+	if got := decoded.GetTimestampBytes(); !bytes.Equal(got, timestampBytes) {
+		t.Errorf("timestamp bytes = %v, want %v", got, timestampBytes)
+	}
+	got, err := decoded.getValuesBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, valueBytes) {
+		t.Errorf("value bytes = %v, want %v", got, valueBytes)
+	}
+}
+
+func TestDecodeValues(t *testing.T) {
+	schemas := []*MeasurementSchema{
+		{Measurement: "restart_count", DataType: INT32},
+		{Measurement: "description", DataType: TEXT},
+	}
+	valid := []byte{0, 0, 0, 7, 0, 0, 0, 2, 'o', 'k'}
+	tests := []struct {
+		name       string
+		valueBytes []byte
+		want       []interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "Valid",
+			valueBytes: valid,
+			want:       []interface{}{[]int32{7}, []string{"ok"}},
+		}, {
+			name:       "Truncated",
+			valueBytes: valid[:9],
+			wantErr:    true,
+		}, {
+			name:       "Trailing bytes",
+			valueBytes: append(append([]byte{}, valid...), 0),
+			wantErr:    true,
+		}, {
+			name:       "Negative text length",
+			valueBytes: []byte{0, 0, 0, 7, 0xff, 0xff, 0xff, 0xff},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeValues(tt.valueBytes, schemas, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := DecodeValues(valid, schemas, math.MaxInt32); err == nil {
+		t.Error("DecodeValues() accepted a row count larger than the buffer")
+	}
+}
+
+func TestDecodeTimestamps(t *testing.T) {
+	if _, err := DecodeTimestamps(make([]byte, 12), 2); err == nil {
+		t.Error("DecodeTimestamps() accepted 12 bytes for 2 rows")
+	}
+	got, err := DecodeTimestamps(append(int64ToBytes(-1), int64ToBytes(1<<40)...), 2)
+	if err != nil || !reflect.DeepEqual(got, []int64{-1, 1 << 40}) {
+		t.Errorf("DecodeTimestamps() = %v, %v", got, err)
+	}
+	if _, err := DecodeTimestamps(nil, 1<<61); err == nil {
+		t.Error("DecodeTimestamps() accepted a row count overflowing the byte count")
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+// DeviceInfo describes a device returned by GetDevices.
+type DeviceInfo struct {
+	Path string
+	// Aligned is only reported by the servers knowing aligned time series, it
+	// is false otherwise.
+	Aligned bool
+}
+
+// The columns of the SHOW DEVICES result.
+const (
+	devicesColumnName   = "devices"
+	isAlignedColumnName = "isAligned"
+)
+
+// GetDevices returns the devices matching a path pattern, such as root.sg.*,
+// with SHOW DEVICES. The slice is empty when no device matches.
+func (s *Session) GetDevices(pathPattern string) ([]DeviceInfo, error) {
+	return s.GetDevicesPage(pathPattern, 0, 0)
+}
+
+// GetDevicesPage is GetDevices returning at most limit devices after skipping
+// offset of them, to page through large device trees. A limit of 0 returns
+// all the devices after offset.
+func (s *Session) GetDevicesPage(pathPattern string, limit, offset int) ([]DeviceInfo, error) {
+	sql, err := showDevicesStatement(pathPattern, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	dataSet, err := s.executeQuery(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer dataSet.Close()
+	return readDevices(dataSet)
+}
+
+func showDevicesStatement(pathPattern string, limit, offset int) (string, error) {
+	if limit < 0 || offset < 0 {
+		return "", fmt.Errorf("illegal limit %d or offset %d", limit, offset)
+	}
+	if strings.ContainsAny(pathPattern, " ;") {
+		return "", fmt.Errorf("illegal path pattern %s", pathPattern)
+	}
+	sql := "show devices"
+	if pathPattern != "" {
+		sql += " " + pathPattern
+	}
+	if limit > 0 {
+		sql += fmt.Sprintf(" limit %d", limit)
+	}
+	if offset > 0 {
+		sql += fmt.Sprintf(" offset %d", offset)
+	}
+	return sql, nil
+}
+
+func readDevices(dataSet *SessionDataSet) ([]DeviceInfo, error) {
+	hasAligned := false
+	for _, columnName := range dataSet.GetColumnNames() {
+		if columnName == isAlignedColumnName {
+			hasAligned = true
+		}
+	}
+	devices := make([]DeviceInfo, 0)
+	for {
+		hasNext, err := dataSet.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !hasNext {
+			return devices, nil
+		}
+		device := DeviceInfo{Path: dataSet.GetText(devicesColumnName)}
+		if hasAligned {
+			device.Aligned = dataSet.GetText(isAlignedColumnName) == "true"
+		}
+		devices = append(devices, device)
+	}
+}
+
+// errSubtreeWildcardUnsupported reports a server without the ** wildcard.
+var errSubtreeWildcardUnsupported = errors.New("the server doesn't support the ** wildcard")
+
+// DeleteDevice deletes all the time series under a device path, with their
+// data, and returns their paths: those of the device and of the devices below
+// it, such as root.sg.d1.sub.temperature for root.sg.d1. With dryRun nothing is
+// deleted and the status is nil, the paths are those which would be deleted. A
+// device without time series is an error.
+func (s *Session) DeleteDevice(deviceId string, dryRun bool) (paths []string, r *rpc.TSStatus, err error) {
+	if err := ValidatePath(deviceId); err != nil {
+		return nil, nil, err
+	}
+	timeseries, _, err := s.ShowTimeseries(s.subtreePattern(deviceId), TimeseriesFilter{}, 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	paths = deviceTimeseries(deviceId, timeseries)
+	if len(paths) == 0 {
+		return nil, nil, fmt.Errorf("device %s has no time series", deviceId)
+	}
+	if dryRun {
+		return paths, nil, nil
+	}
+	r, err = s.DeleteTimeseries(paths)
+	return paths, r, err
+}
+
+// subtreePattern returns the path pattern matching all the time series under
+// a device: device.** since 0.13, device.* before, which those servers match
+// against the whole subtree.
+func (s *Session) subtreePattern(deviceId string) string {
+	if err := s.checkServerVersion(0, 13, "the ** wildcard", errSubtreeWildcardUnsupported); err != nil {
+		return deviceId + ".*"
+	}
+	return deviceId + ".**"
+}
+
+// deviceTimeseries returns the paths of the time series under a device.
+func deviceTimeseries(deviceId string, timeseries []TimeseriesInfo) []string {
+	paths := make([]string, 0, len(timeseries))
+	prefix := deviceId + "."
+	for _, info := range timeseries {
+		if strings.HasPrefix(info.Path, prefix) {
+			paths = append(paths, info.Path)
+		}
+	}
+	return paths
+}
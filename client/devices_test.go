@@ -0,0 +1,168 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func Test_showDevicesStatement(t *testing.T) {
+	tests := []struct {
+		name        string
+		pathPattern string
+		limit       int
+		offset      int
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "All",
+			want: "show devices",
+		}, {
+			name:        "Page",
+			pathPattern: "root.sg.*",
+			limit:       10,
+			offset:      20,
+			want:        "show devices root.sg.* limit 10 offset 20",
+		}, {
+			name:    "Negative limit",
+			limit:   -1,
+			wantErr: true,
+		}, {
+			name:        "Injection",
+			pathPattern: "root.sg; delete timeseries root.**",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := showDevicesStatement(tt.pathPattern, tt.limit, tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("showDevicesStatement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("showDevicesStatement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readDevices(t *testing.T) {
+	text := func(s string) []byte {
+		return append(int32ToBytes(int32(len(s))), s...)
+	}
+	tests := []struct {
+		name    string
+		columns []string
+		types   []string
+		data    rpc.TSQueryDataSet
+		want    []DeviceInfo
+	}{
+		{
+			name:    "Devices",
+			columns: []string{devicesColumnName},
+			types:   []string{"TEXT"},
+			data: rpc.TSQueryDataSet{
+				Time:       make([]byte, 16),
+				ValueList:  [][]byte{append(text("root.sg.d1"), text("root.sg.d2")...)},
+				BitmapList: [][]byte{{192}},
+			},
+			want: []DeviceInfo{{Path: "root.sg.d1"}, {Path: "root.sg.d2"}},
+		}, {
+			name:    "Aligned",
+			columns: []string{devicesColumnName, isAlignedColumnName},
+			types:   []string{"TEXT", "TEXT"},
+			data: rpc.TSQueryDataSet{
+				Time:       make([]byte, 8),
+				ValueList:  [][]byte{text("root.sg.d1"), text("true")},
+				BitmapList: [][]byte{{128}, {128}},
+			},
+			want: []DeviceInfo{{Path: "root.sg.d1", Aligned: true}},
+		}, {
+			name:    "No match",
+			columns: []string{devicesColumnName},
+			types:   []string{"TEXT"},
+			data:    rpc.TSQueryDataSet{},
+			want:    []DeviceInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := NewSessionDataSet("", tt.columns, tt.types, nil, 1, nil, 1, &tt.data, true, DefaultFetchSize)
+			ds.ioTDBRpcDataSet.emptyResultSet = true
+			got, err := readDevices(ds)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readDevices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_deviceTimeseries(t *testing.T) {
+	timeseries := []TimeseriesInfo{
+		{Path: "root.sg.d1.temperature"},
+		{Path: "root.sg.d1.`status.code`"},
+		{Path: "root.sg.d1.sub.temperature"},
+		{Path: "root.sg.d10.temperature"},
+	}
+	got := deviceTimeseries("root.sg.d1", timeseries)
+	want := []string{"root.sg.d1.temperature", "root.sg.d1.`status.code`", "root.sg.d1.sub.temperature"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deviceTimeseries() = %v, want %v", got, want)
+	}
+}
+
+func TestSession_subtreePattern(t *testing.T) {
+	tests := []struct {
+		name   string
+		client thrift.TClient
+		want   string
+	}{
+		{
+			name:   "Double wildcard",
+			client: &propertiesClient{version: "0.13.0"},
+			want:   "root.sg.d1.**",
+		}, {
+			name:   "Older server",
+			client: &propertiesClient{version: "0.12.4"},
+			want:   "root.sg.d1.*",
+		}, {
+			name:   "Unknown version",
+			client: &failingClient{err: errors.New("connection reset by peer")},
+			want:   "root.sg.d1.**",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Session{config: &Config{Logger: &recordingLogger{}}, client: rpc.NewTSIServiceClient(tt.client)}
+			if got := s.subtreePattern("root.sg.d1"); got != tt.want {
+				t.Errorf("Session.subtreePattern() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
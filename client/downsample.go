@@ -0,0 +1,234 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// AggFunc is an aggregation used by Tablet.Downsample.
+type AggFunc int
+
+const (
+	AggMean AggFunc = iota
+	AggMin
+	AggMax
+	AggSum
+	AggLast
+)
+
+// Downsample groups the rows into buckets of interval aligned on multiples of
+// interval, [k*interval, (k+1)*interval), and reduces every numeric column of a
+// bucket with agg, returning a new tablet with one row per bucket stamped with
+// the start of the bucket. BOOLEAN and TEXT columns always keep the last value
+// of the bucket. Buckets without rows produce no row, and a cell is unset when
+// the column has no set cell in its bucket. Results keep the type of their
+// column: INT32 and INT64 columns are aggregated in int64, their means rounded
+// half away from zero, and a sum overflowing the column type is an error. FLOAT
+// and DOUBLE columns are aggregated in float64.
+func (t *Tablet) Downsample(interval int64, agg AggFunc) (*Tablet, error) {
+	if interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+	if agg < AggMean || agg > AggLast {
+		return nil, fmt.Errorf("illegal aggregation %d", agg)
+	}
+	index := sortedIndex(t.timestamps[:t.rowCount])
+	buckets := make([][]int, 0)
+	bucketTimes := make([]int64, 0)
+	for _, row := range index {
+		bucket := floorDiv(t.timestamps[row], interval) * interval
+		if len(bucketTimes) == 0 || bucketTimes[len(bucketTimes)-1] != bucket {
+			bucketTimes = append(bucketTimes, bucket)
+			buckets = append(buckets, make([]int, 0))
+		}
+		buckets[len(buckets)-1] = append(buckets[len(buckets)-1], row)
+	}
+
+	downsampled, err := NewTablet(t.deviceId, t.measurementSchemas, len(buckets))
+	if err != nil {
+		return nil, err
+	}
+	for i, rows := range buckets {
+		downsampled.SetTimestamp(bucketTimes[i], i)
+		for columnIndex, schema := range t.measurementSchemas {
+			value, err := t.aggregate(columnIndex, rows, agg)
+			if err != nil {
+				return nil, err
+			}
+			if value == nil {
+				continue
+			}
+			if err := downsampled.SetValueAt(value, columnIndex, i); err != nil {
+				return nil, fmt.Errorf("column %s: %v", schema.Measurement, err)
+			}
+		}
+	}
+	downsampled.sorted = true
+	return downsampled, nil
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// aggregate reduces the set cells of rows in a column, it returns nil if there
+// is none.
+func (t *Tablet) aggregate(columnIndex int, rows []int, agg AggFunc) (interface{}, error) {
+	dataType := t.measurementSchemas[columnIndex].DataType
+	if dataType == BOOLEAN || dataType == TEXT {
+		agg = AggLast
+	}
+	if dataType == INT32 || dataType == INT64 {
+		return t.aggregateInteger(columnIndex, rows, agg)
+	}
+	var (
+		result interface{}
+		best   float64
+		sum    float64
+		count  int
+	)
+	for _, row := range rows {
+		if !t.isSet(columnIndex, row) {
+			continue
+		}
+		value, err := t.GetValueAt(columnIndex, row)
+		if err != nil {
+			return nil, err
+		}
+		if agg == AggLast {
+			result = value
+			continue
+		}
+		f := toFloat64(value)
+		if count == 0 || (agg == AggMin && f < best) || (agg == AggMax && f > best) {
+			best = f
+			result = value
+		}
+		sum += f
+		count++
+	}
+	if count == 0 || (agg != AggMean && agg != AggSum) {
+		return result, nil
+	}
+	if agg == AggMean {
+		sum /= float64(count)
+	}
+	if dataType == FLOAT {
+		return float32(sum), nil
+	}
+	return sum, nil
+}
+
+// aggregateInteger reduces the set cells of rows in an INT32 or INT64 column in
+// int64, it returns nil if there is none. The sum of a mean is a big.Int, the
+// mean of int64 values fits in int64 even when their sum doesn't.
+func (t *Tablet) aggregateInteger(columnIndex int, rows []int, agg AggFunc) (interface{}, error) {
+	schema := t.measurementSchemas[columnIndex]
+	var (
+		result int64
+		sum    int64
+		total  big.Int
+		count  int64
+	)
+	for _, row := range rows {
+		if !t.isSet(columnIndex, row) {
+			continue
+		}
+		value, err := t.GetValueAt(columnIndex, row)
+		if err != nil {
+			return nil, err
+		}
+		v := toInt64(value)
+		if count == 0 || agg == AggLast || (agg == AggMin && v < result) || (agg == AggMax && v > result) {
+			result = v
+		}
+		switch agg {
+		case AggSum:
+			if v > 0 && sum > math.MaxInt64-v || v < 0 && sum < math.MinInt64-v {
+				return nil, fmt.Errorf("column %s: sum overflows INT64", schema.Measurement)
+			}
+			sum += v
+		case AggMean:
+			total.Add(&total, big.NewInt(v))
+		}
+		count++
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	switch agg {
+	case AggSum:
+		result = sum
+	case AggMean:
+		result = roundDiv(&total, count)
+	}
+	if schema.DataType == INT32 {
+		if result > math.MaxInt32 || result < math.MinInt32 {
+			return nil, fmt.Errorf("column %s: sum %d overflows INT32", schema.Measurement, result)
+		}
+		return int32(result), nil
+	}
+	return result, nil
+}
+
+// roundDiv divides a by b > 0, rounding half away from zero, the quotient must
+// fit in int64.
+func roundDiv(a *big.Int, b int64) int64 {
+	divisor := big.NewInt(b)
+	q, r := new(big.Int).QuoRem(a, divisor, new(big.Int))
+	if r.Lsh(r.Abs(r), 1).Cmp(divisor) >= 0 {
+		q.Add(q, big.NewInt(int64(a.Sign())))
+	}
+	return q.Int64()
+}
+
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	default:
+		return 0
+	}
+}
+
+func toFloat64(value interface{}) float64 {
+	switch v := value.(type) {
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	default:
+		return 0
+	}
+}
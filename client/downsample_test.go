@@ -0,0 +1,165 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTablet_Downsample(t *testing.T) {
+	timestamps := []int64{-3, 1, 4, 12, 11, 25}
+	tests := []struct {
+		name           string
+		agg            AggFunc
+		wantTimestamps []int64
+		wantInt64      []int64
+		wantDouble     []float64
+		wantText       []string
+	}{
+		{
+			name:           "Mean",
+			agg:            AggMean,
+			wantTimestamps: []int64{-10, 0, 10, 20},
+			wantInt64:      []int64{-3, 3, 12, 25},
+			wantDouble:     []float64{-3, 2.5, 11.5, 25},
+			wantText:       []string{"-3", "4", "12", "25"},
+		}, {
+			name:           "Max",
+			agg:            AggMax,
+			wantTimestamps: []int64{-10, 0, 10, 20},
+			wantInt64:      []int64{-3, 4, 12, 25},
+			wantDouble:     []float64{-3, 4, 12, 25},
+			wantText:       []string{"-3", "4", "12", "25"},
+		}, {
+			name:           "Sum",
+			agg:            AggSum,
+			wantTimestamps: []int64{-10, 0, 10, 20},
+			wantInt64:      []int64{-3, 5, 23, 25},
+			wantDouble:     []float64{-3, 5, 23, 25},
+			wantText:       []string{"-3", "4", "12", "25"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, _ := createTablet(len(timestamps))
+			for row, timestamp := range timestamps {
+				tablet.SetTimestamp(timestamp, row)
+				tablet.SetValueAt(timestamp, 2, row)
+				tablet.SetValueAt(float64(timestamp), 1, row)
+				tablet.SetValueAt(int64ToString(timestamp), 4, row)
+			}
+			got, err := tablet.Downsample(10, tt.agg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.timestamps, tt.wantTimestamps) {
+				t.Errorf("Tablet.Downsample() timestamps = %v, want %v", got.timestamps, tt.wantTimestamps)
+			}
+			if !reflect.DeepEqual(got.values[2], tt.wantInt64) {
+				t.Errorf("Tablet.Downsample() INT64 = %v, want %v", got.values[2], tt.wantInt64)
+			}
+			if !reflect.DeepEqual(got.values[1], tt.wantDouble) {
+				t.Errorf("Tablet.Downsample() DOUBLE = %v, want %v", got.values[1], tt.wantDouble)
+			}
+			if !reflect.DeepEqual(got.values[4], tt.wantText) {
+				t.Errorf("Tablet.Downsample() TEXT = %v, want %v", got.values[4], tt.wantText)
+			}
+			if got.isSet(0, 0) {
+				t.Errorf("Tablet.Downsample() set a cell of a column without values")
+			}
+		})
+	}
+}
+
+func TestTablet_Downsample_integers(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataType TSDataType
+		values   []interface{}
+		agg      AggFunc
+		want     interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "Exact INT64 max",
+			dataType: INT64,
+			values:   []interface{}{int64(math.MaxInt64 - 1), int64(math.MaxInt64)},
+			agg:      AggMax,
+			want:     int64(math.MaxInt64),
+		}, {
+			name:     "INT64 mean",
+			dataType: INT64,
+			values:   []interface{}{int64(-3), int64(-4)},
+			agg:      AggMean,
+			want:     int64(-4),
+		}, {
+			name:     "INT64 mean of an overflowing sum",
+			dataType: INT64,
+			values:   []interface{}{int64(math.MaxInt64), int64(math.MaxInt64 - 2)},
+			agg:      AggMean,
+			want:     int64(math.MaxInt64 - 1),
+		}, {
+			name:     "INT64 mean rounded away from zero",
+			dataType: INT64,
+			values:   []interface{}{int64(math.MinInt64), int64(math.MinInt64 + 1)},
+			agg:      AggMean,
+			want:     int64(math.MinInt64),
+		}, {
+			name:     "INT64 sum overflow",
+			dataType: INT64,
+			values:   []interface{}{int64(math.MaxInt64), int64(1)},
+			agg:      AggSum,
+			wantErr:  true,
+		}, {
+			name:     "INT32 sum overflow",
+			dataType: INT32,
+			values:   []interface{}{int32(math.MaxInt32), int32(1)},
+			agg:      AggSum,
+			wantErr:  true,
+		}, {
+			name:     "INT32 mean",
+			dataType: INT32,
+			values:   []interface{}{int32(math.MaxInt32), int32(math.MaxInt32)},
+			agg:      AggMean,
+			want:     int32(math.MaxInt32),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, _ := NewTablet("root.sg.d1", []*MeasurementSchema{{Measurement: "s1", DataType: tt.dataType}}, len(tt.values))
+			for row, value := range tt.values {
+				tablet.SetTimestamp(int64(row), row)
+				tablet.SetValueAt(value, 0, row)
+			}
+			got, err := tablet.Downsample(10, tt.agg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tablet.Downsample() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if value, _ := got.GetValueAt(0, 0); value != tt.want {
+				t.Errorf("Tablet.Downsample() = %v, want %v", value, tt.want)
+			}
+		})
+	}
+}
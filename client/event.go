@@ -0,0 +1,208 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+// Event is one row of a heterogeneous event stream, every event may carry a
+// different subset of measurements.
+type Event struct {
+	Timestamp int64
+	Values    map[string]interface{}
+}
+
+type eventGroup struct {
+	measurements []string
+	dataTypes    []TSDataType
+	events       []*Event
+}
+
+// InsertEvents inserts events of one device without requiring the caller to
+// manage tablet layout. Events sharing the same measurement set are grouped
+// into one tablet, then the cheaper of InsertTablets (one tablet per group)
+// and InsertRecordsOfOneDevice (per-record measurement lists) is used, based
+// on the metadata each request would carry. The data type of a measurement is
+// inferred from the Go type of its value.
+func (s *Session) InsertEvents(deviceId string, events []*Event) (r *rpc.TSStatus, err error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("events can't be empty")
+	}
+
+	sorted := make([]*Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+
+	groups, err := groupEvents(sorted)
+	if err != nil {
+		return nil, err
+	}
+
+	if tabletsOverhead(deviceId, groups) < recordsOverhead(groups) {
+		tablets, err := eventGroupsToTablets(deviceId, groups)
+		if err != nil {
+			return nil, err
+		}
+		return s.InsertTablets(tablets, true)
+	}
+
+	length := len(sorted)
+	timestamps := make([]int64, length)
+	measurementsSlice := make([][]string, length)
+	dataTypesSlice := make([][]TSDataType, length)
+	valuesSlice := make([][]interface{}, length)
+	for i, event := range sorted {
+		measurements := sortedMeasurements(event)
+		dataTypes := make([]TSDataType, len(measurements))
+		values := make([]interface{}, len(measurements))
+		for j, measurement := range measurements {
+			values[j] = event.Values[measurement]
+			dataTypes[j], _ = dataTypeOf(values[j])
+		}
+		timestamps[i] = event.Timestamp
+		measurementsSlice[i] = measurements
+		dataTypesSlice[i] = dataTypes
+		valuesSlice[i] = values
+	}
+	return s.InsertRecordsOfOneDevice(deviceId, timestamps, measurementsSlice, dataTypesSlice, valuesSlice, true)
+}
+
+func sortedMeasurements(event *Event) []string {
+	measurements := make([]string, 0, len(event.Values))
+	for measurement := range event.Values {
+		measurements = append(measurements, measurement)
+	}
+	sort.Strings(measurements)
+	return measurements
+}
+
+// groupEvents groups events by their measurement names and data types, keeping
+// the order in which the groups first appear.
+func groupEvents(events []*Event) ([]*eventGroup, error) {
+	groups := make([]*eventGroup, 0)
+	index := make(map[string]*eventGroup)
+	for i, event := range events {
+		if event == nil || len(event.Values) == 0 {
+			return nil, fmt.Errorf("events[%d] has no values", i)
+		}
+		measurements := sortedMeasurements(event)
+		dataTypes := make([]TSDataType, len(measurements))
+		keyParts := make([]string, len(measurements))
+		for j, measurement := range measurements {
+			dataType, err := dataTypeOf(event.Values[measurement])
+			if err != nil {
+				return nil, fmt.Errorf("events[%d] measurement %s: %v", i, measurement, err)
+			}
+			dataTypes[j] = dataType
+			keyParts[j] = fmt.Sprintf("%s:%d", measurement, dataType)
+		}
+		key := strings.Join(keyParts, ",")
+		group, exists := index[key]
+		if !exists {
+			group = &eventGroup{measurements: measurements, dataTypes: dataTypes}
+			index[key] = group
+			groups = append(groups, group)
+		}
+		group.events = append(group.events, event)
+	}
+	return groups, nil
+}
+
+// recordsOverhead is the number of bytes InsertRecordsOfOneDevice spends on
+// metadata: every record repeats its measurement names and value types.
+func recordsOverhead(groups []*eventGroup) int {
+	size := 0
+	for _, group := range groups {
+		perRecord := 8
+		for _, measurement := range group.measurements {
+			perRecord += 4 + len(measurement) + 2
+		}
+		size += perRecord * len(group.events)
+	}
+	return size
+}
+
+// tabletsOverhead is the number of bytes InsertTablets spends on metadata:
+// every tablet repeats the device id, its measurement names and types.
+func tabletsOverhead(deviceId string, groups []*eventGroup) int {
+	size := 0
+	for _, group := range groups {
+		size += 4 + len(deviceId) + 4
+		for _, measurement := range group.measurements {
+			size += 4 + len(measurement) + 4
+		}
+	}
+	return size
+}
+
+func eventGroupsToTablets(deviceId string, groups []*eventGroup) ([]*Tablet, error) {
+	tablets := make([]*Tablet, len(groups))
+	for i, group := range groups {
+		schemas := make([]*MeasurementSchema, len(group.measurements))
+		for j, measurement := range group.measurements {
+			schemas[j] = &MeasurementSchema{
+				Measurement: measurement,
+				DataType:    group.dataTypes[j],
+			}
+		}
+		tablet, err := NewTablet(deviceId, schemas, len(group.events))
+		if err != nil {
+			return nil, err
+		}
+		for row, event := range group.events {
+			tablet.SetTimestamp(event.Timestamp, row)
+			for column, measurement := range group.measurements {
+				if err := tablet.SetValueAt(event.Values[measurement], column, row); err != nil {
+					return nil, err
+				}
+			}
+		}
+		tablets[i] = tablet
+	}
+	return tablets, nil
+}
+
+// dataTypeOf returns the TSDataType matching the Go type of value.
+func dataTypeOf(value interface{}) (TSDataType, error) {
+	switch value.(type) {
+	case bool:
+		return BOOLEAN, nil
+	case int32:
+		return INT32, nil
+	case int64:
+		return INT64, nil
+	case float32:
+		return FLOAT, nil
+	case float64:
+		return DOUBLE, nil
+	case string:
+		return TEXT, nil
+	default:
+		return UNKNOW, fmt.Errorf("unsupported value %v(%v)", value, reflect.TypeOf(value))
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_groupEvents(t *testing.T) {
+	events := []*Event{
+		{Timestamp: 1, Values: map[string]interface{}{"temperature": float32(36.5), "status": true}},
+		{Timestamp: 2, Values: map[string]interface{}{"description": "restart"}},
+		{Timestamp: 3, Values: map[string]interface{}{"status": false, "temperature": float32(37.1)}},
+	}
+	groups, err := groupEvents(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("groupEvents() got %d groups, want 2", len(groups))
+	}
+	if want := []string{"status", "temperature"}; !reflect.DeepEqual(groups[0].measurements, want) {
+		t.Errorf("groupEvents() measurements = %v, want %v", groups[0].measurements, want)
+	}
+	if want := []TSDataType{BOOLEAN, FLOAT}; !reflect.DeepEqual(groups[0].dataTypes, want) {
+		t.Errorf("groupEvents() dataTypes = %v, want %v", groups[0].dataTypes, want)
+	}
+	if len(groups[0].events) != 2 || len(groups[1].events) != 1 {
+		t.Errorf("groupEvents() events per group = %d, %d, want 2, 1", len(groups[0].events), len(groups[1].events))
+	}
+
+	if _, err := groupEvents([]*Event{{Timestamp: 1, Values: map[string]interface{}{"count": 1}}}); err == nil {
+		t.Error("groupEvents() expected an error for an int value")
+	}
+}
+
+func Test_eventGroupsToTablets(t *testing.T) {
+	events := []*Event{
+		{Timestamp: 1, Values: map[string]interface{}{"restart_count": int32(1), "tick_count": int64(10)}},
+		{Timestamp: 2, Values: map[string]interface{}{"restart_count": int32(2), "tick_count": int64(20)}},
+	}
+	groups, err := groupEvents(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tablets, err := eventGroupsToTablets("root.ln.device1", groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tablets) != 1 || tablets[0].GetRowCount() != 2 {
+		t.Fatalf("eventGroupsToTablets() got %d tablets", len(tablets))
+	}
+	if got, _ := tablets[0].GetValueAt(1, 1); got != int64(20) {
+		t.Errorf("eventGroupsToTablets() value = %v, want 20", got)
+	}
+}
+
+func Test_insertEventsOverhead(t *testing.T) {
+	dense := make([]*Event, 100)
+	for i := range dense {
+		dense[i] = &Event{Timestamp: int64(i), Values: map[string]interface{}{"temperature": float64(i)}}
+	}
+	groups, _ := groupEvents(dense)
+	if tabletsOverhead("root.ln.device1", groups) >= recordsOverhead(groups) {
+		t.Error("expected tablets to be cheaper for events sharing one measurement set")
+	}
+
+	sparse := []*Event{
+		{Timestamp: 1, Values: map[string]interface{}{"a": int32(1)}},
+		{Timestamp: 2, Values: map[string]interface{}{"b": int32(1)}},
+		{Timestamp: 3, Values: map[string]interface{}{"c": int32(1)}},
+	}
+	groups, _ = groupEvents(sparse)
+	if tabletsOverhead("root.ln.device1", groups) < recordsOverhead(groups) {
+		t.Error("expected records to be cheaper for events with distinct measurement sets")
+	}
+}
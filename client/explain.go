@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"strings"
+)
+
+// Explain returns the plan of a query as the server prints it, the rows of the
+// EXPLAIN result joined by new lines, their columns separated by tabs. The
+// text isn't parsed, its layout differs between server releases.
+func (s *Session) Explain(sql string) (string, error) {
+	return s.explain("explain ", sql)
+}
+
+// ExplainAnalyze executes the query and returns the plan annotated with its
+// statistics, in the form of Explain. Only recent server releases know
+// EXPLAIN ANALYZE, the 0.1x ones fail the statement.
+func (s *Session) ExplainAnalyze(sql string) (string, error) {
+	return s.explain("explain analyze ", sql)
+}
+
+func (s *Session) explain(prefix string, sql string) (string, error) {
+	if strings.TrimSpace(sql) == "" {
+		return "", errors.New("sql can't be empty")
+	}
+	dataSet, err := s.executeQuery(prefix + sql)
+	if err != nil {
+		return "", err
+	}
+	defer dataSet.Close()
+	return readPlan(dataSet)
+}
+
+func readPlan(dataSet *SessionDataSet) (string, error) {
+	lines := make([]string, 0)
+	for {
+		hasNext, err := dataSet.Next()
+		if err != nil {
+			return "", err
+		}
+		if !hasNext {
+			return strings.Join(lines, "\n"), nil
+		}
+		record, err := dataSet.GetRowRecord()
+		if err != nil {
+			return "", err
+		}
+		fields := record.GetFields()
+		texts := make([]string, len(fields))
+		for i, field := range fields {
+			texts[i] = field.GetText()
+		}
+		lines = append(lines, strings.Join(texts, "\t"))
+	}
+}
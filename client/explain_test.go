@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func Test_readPlan(t *testing.T) {
+	text := func(s string) []byte {
+		return append(int32ToBytes(int32(len(s))), s...)
+	}
+	data := rpc.TSQueryDataSet{
+		Time:       make([]byte, 16),
+		ValueList:  [][]byte{append(text("SeriesScan"), text("Filter")...), append(text("root.sg.d1.s1"), text("time > 0")...)},
+		BitmapList: [][]byte{{192}, {192}},
+	}
+	ds := NewSessionDataSet("", []string{"operator", "detail"}, []string{"TEXT", "TEXT"}, nil, 1, nil, 1, &data, true, DefaultFetchSize)
+	ds.ioTDBRpcDataSet.emptyResultSet = true
+	got, err := readPlan(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SeriesScan\troot.sg.d1.s1\nFilter\ttime > 0"; got != want {
+		t.Errorf("readPlan() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MaxInterpolatedRows is the largest number of rows Interpolate returns, it
+// fails instead of allocating a larger tablet for gaps far apart.
+const MaxInterpolatedRows = 1 << 24
+
+// InterpMethod is how Tablet.Interpolate fills the missing rows.
+type InterpMethod int
+
+const (
+	// InterpLinear interpolates the numeric columns between the rows around
+	// the gap, it carries the previous value forward for BOOLEAN and TEXT.
+	InterpLinear InterpMethod = iota
+	// InterpPrevious carries the previous value of each column forward.
+	InterpPrevious
+	// InterpNull leaves the cells of the missing rows unset.
+	InterpNull
+)
+
+// Interpolate returns a new tablet, sorted by time, where the gaps longer than
+// expectedInterval between consecutive rows are filled with rows every
+// expectedInterval after the row before the gap. It only makes sense for a
+// regularly sampled series, and the returned tablet has more rows than t when
+// there are gaps. A missing cell is left unset when there is no value to
+// interpolate from: no previous set cell, or for InterpLinear a row around the
+// gap without a value in the column. INT32 and INT64 interpolations are
+// rounded.
+func (t *Tablet) Interpolate(expectedInterval int64, method InterpMethod) (*Tablet, error) {
+	if expectedInterval <= 0 {
+		return nil, errors.New("expectedInterval must be positive")
+	}
+	if method < InterpLinear || method > InterpNull {
+		return nil, fmt.Errorf("illegal interpolation method %d", method)
+	}
+	index := sortedIndex(t.timestamps[:t.rowCount])
+	rowCount := uint64(len(index))
+	for i := 1; i < len(index); i++ {
+		missing := missingRows(t.timestamps[index[i-1]], t.timestamps[index[i]], expectedInterval)
+		if rowCount > MaxInterpolatedRows || missing > MaxInterpolatedRows-rowCount {
+			return nil, fmt.Errorf("interpolating every %d would create more than %d rows", expectedInterval, MaxInterpolatedRows)
+		}
+		rowCount += missing
+	}
+
+	interpolated, err := NewTablet(t.deviceId, t.measurementSchemas, int(rowCount))
+	if err != nil {
+		return nil, err
+	}
+	// previous holds the last set value of each column.
+	previous := make([]interface{}, len(t.measurementSchemas))
+	row := 0
+	for i, from := range index {
+		if i > 0 {
+			before, after := index[i-1], from
+			missing := missingRows(t.timestamps[before], t.timestamps[after], expectedInterval)
+			for k := int64(1); k <= int64(missing); k++ {
+				timestamp := t.timestamps[before] + k*expectedInterval
+				interpolated.SetTimestamp(timestamp, row)
+				if method != InterpNull {
+					for columnIndex := range t.measurementSchemas {
+						value, err := t.interpolateValue(columnIndex, before, after, timestamp, method, previous[columnIndex])
+						if err != nil {
+							return nil, err
+						}
+						if value == nil {
+							continue
+						}
+						if err := interpolated.SetValueAt(value, columnIndex, row); err != nil {
+							return nil, err
+						}
+					}
+				}
+				row++
+			}
+		}
+		interpolated.SetTimestamp(t.timestamps[from], row)
+		for columnIndex := range t.measurementSchemas {
+			if !t.isSet(columnIndex, from) {
+				continue
+			}
+			value, err := t.GetValueAt(columnIndex, from)
+			if err != nil {
+				return nil, err
+			}
+			if err := interpolated.SetValueAt(value, columnIndex, row); err != nil {
+				return nil, err
+			}
+			previous[columnIndex] = value
+		}
+		row++
+	}
+	interpolated.sorted = true
+	return interpolated, nil
+}
+
+// missingRows returns the number of rows to add every interval between two
+// consecutive timestamps. The gap is computed unsigned, it overflows int64 for
+// timestamps of opposite signs far apart.
+func missingRows(before, after, interval int64) uint64 {
+	gap := uint64(after) - uint64(before)
+	if gap <= uint64(interval) {
+		return 0
+	}
+	return (gap - 1) / uint64(interval)
+}
+
+func (t *Tablet) interpolateValue(columnIndex, before, after int, timestamp int64, method InterpMethod, previous interface{}) (interface{}, error) {
+	dataType := t.measurementSchemas[columnIndex].DataType
+	if method == InterpPrevious || dataType == BOOLEAN || dataType == TEXT {
+		return previous, nil
+	}
+	if !t.isSet(columnIndex, before) || !t.isSet(columnIndex, after) {
+		return nil, nil
+	}
+	beforeValue, err := t.GetValueAt(columnIndex, before)
+	if err != nil {
+		return nil, err
+	}
+	afterValue, err := t.GetValueAt(columnIndex, after)
+	if err != nil {
+		return nil, err
+	}
+	ratio := float64(timestamp-t.timestamps[before]) / (float64(t.timestamps[after]) - float64(t.timestamps[before]))
+	start := toFloat64(beforeValue)
+	return fromFloat64(start+(toFloat64(afterValue)-start)*ratio, dataType), nil
+}
+
+// fromFloat64 converts an interpolated value to dataType, integers are rounded
+// half away from zero.
+func fromFloat64(value float64, dataType TSDataType) interface{} {
+	switch dataType {
+	case INT32:
+		return int32(math.Round(value))
+	case INT64:
+		return int64(math.Round(value))
+	case FLOAT:
+		return float32(value)
+	default:
+		return value
+	}
+}
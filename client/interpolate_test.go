@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTablet_Interpolate(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         InterpMethod
+		wantTimestamps []int64
+		wantInt64      []int64
+		wantText       []string
+		wantSet        []bool
+	}{
+		{
+			name:           "Linear",
+			method:         InterpLinear,
+			wantTimestamps: []int64{0, 10, 20, 30, 35, 45},
+			wantInt64:      []int64{0, 10, 20, 30, 35, 0},
+			wantText:       []string{"a", "a", "a", "b", "c", "c"},
+			wantSet:        []bool{true, true, true, true, true, true},
+		}, {
+			name:           "Previous",
+			method:         InterpPrevious,
+			wantTimestamps: []int64{0, 10, 20, 30, 35, 45},
+			wantInt64:      []int64{0, 0, 0, 30, 35, 35},
+			wantText:       []string{"a", "a", "a", "b", "c", "c"},
+			wantSet:        []bool{true, true, true, true, true, true},
+		}, {
+			name:           "Null",
+			method:         InterpNull,
+			wantTimestamps: []int64{0, 10, 20, 30, 35, 45},
+			wantInt64:      []int64{0, 0, 0, 30, 35, 0},
+			wantText:       []string{"a", "", "", "b", "c", ""},
+			wantSet:        []bool{true, false, false, true, true, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, _ := NewTablet("root.ln.device1", []*MeasurementSchema{
+				{Measurement: "tick_count", DataType: INT64},
+				{Measurement: "description", DataType: TEXT},
+			}, 4)
+			// Unsorted, with a gap of three intervals and a gap of two.
+			for row, timestamp := range []int64{30, 0, 35, 55} {
+				tablet.SetTimestamp(timestamp, row)
+			}
+			tablet.SetValueAt(int64(30), 0, 0)
+			tablet.SetValueAt(int64(0), 0, 1)
+			tablet.SetValueAt(int64(35), 0, 2)
+			tablet.SetValueAt("b", 1, 0)
+			tablet.SetValueAt("a", 1, 1)
+			tablet.SetValueAt("c", 1, 2)
+			tablet.SetValueAt("d", 1, 3)
+
+			got, err := tablet.Interpolate(10, tt.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantTimestamps := append(tt.wantTimestamps, 55)
+			if !reflect.DeepEqual(got.timestamps, wantTimestamps) {
+				t.Fatalf("Tablet.Interpolate() timestamps = %v, want %v", got.timestamps, wantTimestamps)
+			}
+			if !reflect.DeepEqual(got.values[0].([]int64)[:len(tt.wantInt64)], tt.wantInt64) {
+				t.Errorf("Tablet.Interpolate() INT64 = %v, want %v", got.values[0], tt.wantInt64)
+			}
+			if !reflect.DeepEqual(got.values[1].([]string)[:len(tt.wantText)], tt.wantText) {
+				t.Errorf("Tablet.Interpolate() TEXT = %v, want %v", got.values[1], tt.wantText)
+			}
+			for row, want := range tt.wantSet {
+				if got.isSet(1, row) != want {
+					t.Errorf("Tablet.Interpolate() row %d set = %v, want %v", row, got.isSet(1, row), want)
+				}
+			}
+			if got.isSet(0, 5) != (tt.method == InterpPrevious) {
+				t.Errorf("Tablet.Interpolate() set a linear value without the next value")
+			}
+		})
+	}
+}
+
+func TestTablet_Interpolate_tooManyRows(t *testing.T) {
+	tests := []struct {
+		name       string
+		timestamps []int64
+	}{
+		{
+			name:       "Large gap",
+			timestamps: []int64{0, 1e18},
+		}, {
+			name:       "Overflowing gap",
+			timestamps: []int64{math.MinInt64, math.MaxInt64},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, _ := NewTablet("root.ln.device1", []*MeasurementSchema{{Measurement: "tick_count", DataType: INT64}}, len(tt.timestamps))
+			for row, timestamp := range tt.timestamps {
+				tablet.SetTimestamp(timestamp, row)
+			}
+			if _, err := tablet.Interpolate(1, InterpNull); err == nil {
+				t.Error("Tablet.Interpolate() returned no error")
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+/*
+ *load a TsFile into IoTDB
+ *params
+ *path: string, path of the TsFile on the server, or of a directory to load all its TsFiles
+ *return
+ *status of the load, its message carries the reason of a failure such as a schema mismatch
+ *
+ *The RPC protocol has no file transfer, the server executes LOAD and reads the file
+ *from its own file system, so the path must be readable by the server process and
+ *can't be checked by the client.
+ */
+func (s *Session) LoadTsFile(path string) (r *rpc.TSStatus, err error) {
+	sql, err := loadStatement(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.ExecuteNonQueryStatement(sql)
+}
+
+func loadStatement(path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", errors.New("path can't be empty")
+	}
+	if strings.Contains(path, "\"") {
+		return "", errors.New("path can't contain a double quote")
+	}
+	return "load \"" + path + "\"", nil
+}
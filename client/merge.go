@@ -0,0 +1,44 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import "github.com/apache/iotdb-client-go/rpc"
+
+/*
+ *trigger the merge (compaction) of the unsequence data with the sequence data
+ *return
+ *status of the command, success means the merge was accepted
+ *
+ *The merge runs asynchronously on the server, the call returns once it is
+ *scheduled. The server merges all the storage groups, MERGE takes no storage
+ *group, and no RPC or statement reports its progress, the server log does.
+ */
+func (s *Session) Merge() (r *rpc.TSStatus, err error) {
+	return s.ExecuteNonQueryStatement("merge")
+}
+
+/*
+ *trigger a full merge, which rewrites whole files instead of appending to them
+ *return
+ *status of the command, see Merge
+ */
+func (s *Session) FullMerge() (r *rpc.TSStatus, err error) {
+	return s.ExecuteNonQueryStatement("full merge")
+}
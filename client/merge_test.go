@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func TestSession_Merge(t *testing.T) {
+	tests := []struct {
+		name  string
+		merge func(s *Session) (*rpc.TSStatus, error)
+		want  string
+	}{
+		{
+			name:  "Merge",
+			merge: (*Session).Merge,
+			want:  "merge",
+		}, {
+			name:  "Full merge",
+			merge: (*Session).FullMerge,
+			want:  "full merge",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &statementClient{}
+			s := &Session{config: &Config{}, client: rpc.NewTSIServiceClient(c)}
+			status, err := tt.merge(s)
+			if err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			if status.GetCode() != SuccessStatus {
+				t.Errorf("%s() status = %v, want %v", tt.name, status, SuccessStatus)
+			}
+			if len(c.statements) != 1 || c.statements[0] != tt.want {
+				t.Errorf("%s() executed %v, want [%v]", tt.name, c.statements, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidateIdentifier checks that name is a legal path segment: a plain
+// identifier, made of letters, digits and underscores and not only digits, or
+// a segment quoted with backticks as BuildPath quotes it, with its inner
+// backticks doubled. The wildcards * and ** aren't accepted.
+func ValidateIdentifier(name string) error {
+	if len(name) >= 2 && name[0] == quote && name[len(name)-1] == quote {
+		inner := name[1 : len(name)-1]
+		if inner == "" {
+			return fmt.Errorf("identifier %s is empty", name)
+		}
+		if strings.Contains(strings.Replace(inner, string(quote)+string(quote), "", -1), string(quote)) {
+			return fmt.Errorf("identifier %s has a backtick which isn't doubled", name)
+		}
+		return nil
+	}
+	if name == "" {
+		return errors.New("identifier is empty")
+	}
+	if name == "*" || name == "**" {
+		return fmt.Errorf("identifier %s is a wildcard", name)
+	}
+	if needsQuote(name) {
+		return fmt.Errorf("identifier %s must be quoted with backticks, see BuildPath", name)
+	}
+	return nil
+}
+
+// ValidatePath checks that path is a legal device path: root followed by
+// segments accepted by ValidateIdentifier, dots inside backticks don't
+// separate segments. The error names the offending segment.
+func ValidatePath(path string) error {
+	segments, err := rawSegments(path)
+	if err != nil {
+		return fmt.Errorf("path %s: %v", path, err)
+	}
+	if segments[0] != "root" {
+		return fmt.Errorf("path %s doesn't start with root", path)
+	}
+	if len(segments) < 2 {
+		return fmt.Errorf("path %s has no segment after root", path)
+	}
+	for _, segment := range segments[1:] {
+		if err := ValidateIdentifier(segment); err != nil {
+			return fmt.Errorf("path %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// rawSegments splits a path on the dots outside backticks, keeping the quotes.
+func rawSegments(path string) ([]string, error) {
+	segments := make([]string, 0)
+	start := 0
+	inQuote := false
+	for i, r := range path {
+		switch {
+		case r == quote:
+			inQuote = !inQuote
+		case r == '.' && !inQuote:
+			segments = append(segments, path[start:i])
+			start = i + 1
+		}
+	}
+	if inQuote {
+		return nil, errors.New("unterminated backtick")
+	}
+	return append(segments, path[start:]), nil
+}
+
+// checkNames validates the device id and the measurements of an insert when
+// the config enables ValidateNames.
+func (s *Session) checkNames(deviceId string, measurements []string) error {
+	if !s.config.ValidateNames {
+		return nil
+	}
+	return validateNames(deviceId, measurements)
+}
+
+func validateNames(deviceId string, measurements []string) error {
+	if err := ValidatePath(deviceId); err != nil {
+		return err
+	}
+	for _, measurement := range measurements {
+		if err := ValidateIdentifier(measurement); err != nil {
+			return fmt.Errorf("measurement of device %s: %v", deviceId, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import "testing"
+
+func TestValidateIdentifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"s1", false},
+		{"temperature_2", false},
+		{"温度", false},
+		{"`s-1`", false},
+		{"`a``b`", false},
+		{BuildPath("a`b.c"), false},
+		{"s-1", true},
+		{"123", true},
+		{"", true},
+		{"``", true},
+		{"`a`b`", true},
+		{"*", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateIdentifier(tt.name); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateIdentifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"root.sg.d1", false},
+		{"root.sg.`d.1`", false},
+		{BuildPath("root", "sg", "d 1"), false},
+		{"root.sg.d-1", true},
+		{"root.sg..d1", true},
+		{"root.sg.`d1", true},
+		{"sg.d1", true},
+		{"root", true},
+		{"root.sg.*", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if err := ValidatePath(tt.path); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSession_checkNames(t *testing.T) {
+	s := &Session{config: &Config{ValidateNames: true}}
+	if _, err := s.genTSInsertRecordReq("root.sg.d-1", 1, []string{"s1"}, []TSDataType{INT32}, []interface{}{int32(1)}); err == nil {
+		t.Error("genTSInsertRecordReq() accepted an illegal device id")
+	}
+	if _, err := s.genTSInsertRecordReq("root.sg.d1", 1, []string{"s.1"}, []TSDataType{INT32}, []interface{}{int32(1)}); err == nil {
+		t.Error("genTSInsertRecordReq() accepted an illegal measurement")
+	}
+	if _, err := s.genTSInsertRecordReq("root.sg.d1", 1, []string{"s1"}, []TSDataType{INT32}, []interface{}{int32(1)}); err != nil {
+		t.Errorf("genTSInsertRecordReq() error = %v", err)
+	}
+	s.config.ValidateNames = false
+	if _, err := s.genTSInsertRecordReq("root.sg.d-1", 1, []string{"s1"}, []TSDataType{INT32}, []interface{}{int32(1)}); err != nil {
+		t.Errorf("genTSInsertRecordReq() validated names without ValidateNames: %v", err)
+	}
+	if _, _, err := NewTabletWithOptions("root.sg.d1", []*MeasurementSchema{{Measurement: "s-1", DataType: INT32}}, 1, TabletOptions{ValidateNames: true}); err == nil {
+		t.Error("NewTabletWithOptions() accepted an illegal measurement")
+	}
+}
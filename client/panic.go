@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// recoveredPanics counts the panics recovered in the background goroutines.
+var recoveredPanics int64
+
+// PanicError is a panic recovered in a background goroutine of the client,
+// the flush goroutine of a TabletCoalescer or the goroutine of
+// SessionDataSet.Stream, reported as an error instead of crashing the process.
+type PanicError struct {
+	Value interface{}
+	// Stack is the stack of the goroutine when it panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered panic: %v", e.Value)
+}
+
+// RecoveredPanics returns the number of panics recovered in the background
+// goroutines since the process started, each of them is a client bug or a
+// malformed input which should be looked into.
+func RecoveredPanics() int64 {
+	return atomic.LoadInt64(&recoveredPanics)
+}
+
+// catchPanic must be deferred, it recovers a panic of the goroutine and passes
+// it to handle.
+func catchPanic(handle func(err *PanicError)) {
+	if value := recover(); value != nil {
+		atomic.AddInt64(&recoveredPanics, 1)
+		handle(&PanicError{Value: value, Stack: debug.Stack()})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+type panickingClient struct{}
+
+func (c *panickingClient) Call(ctx context.Context, method string, args, result thrift.TStruct) error {
+	panic("malformed response")
+}
+
+func TestTabletCoalescer_flushGroup_recoversPanic(t *testing.T) {
+	var handled []error
+	c := createCoalescer(0)
+	c.config.ErrorHandler = func(tablets []*Tablet, err error) {
+		handled = append(handled, err)
+	}
+	c.session.client = rpc.NewTSIServiceClient(&panickingClient{})
+	tablet, _ := NewTablet("root.sg1.d1", []*MeasurementSchema{{Measurement: "temperature", DataType: FLOAT}}, 1)
+	tablet.SetValueAt(float32(36.5), 0, 0)
+
+	before := RecoveredPanics()
+	for i := 0; i < 2; i++ {
+		c.Add(tablet)
+		c.flushInBackground()
+	}
+	if len(handled) != 2 {
+		t.Fatalf("ErrorHandler called %d times, want 2", len(handled))
+	}
+	if err, ok := handled[0].(*PanicError); !ok || err.Value != "malformed response" || len(err.Stack) == 0 {
+		t.Errorf("ErrorHandler error = %v, want a *PanicError", handled[0])
+	}
+	if got := RecoveredPanics() - before; got != 2 {
+		t.Errorf("RecoveredPanics() grew by %d, want 2", got)
+	}
+}
+
+func TestSessionDataSet_Stream_recoversPanic(t *testing.T) {
+	ds := createSessionDataSet()
+	ds.ioTDBRpcDataSet.client = rpc.NewTSIServiceClient(&panickingClient{})
+	ds.ioTDBRpcDataSet.queryDataSet = nil
+	ds.ioTDBRpcDataSet.emptyResultSet = false
+	rows, errs := ds.Stream(context.Background())
+	for range rows {
+	}
+	if err, ok := (<-errs).(*PanicError); !ok {
+		t.Errorf("SessionDataSet.Stream() error = %v, want a *PanicError", err)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	pathSeparator = "."
+	quote         = '`'
+)
+
+// BuildPath joins path segments with dots. A segment which isn't a plain
+// identifier, made of letters, digits and underscores and not only digits, is
+// quoted with backticks, backticks inside it are doubled. The wildcards * and
+// ** are kept as they are.
+func BuildPath(segments ...string) string {
+	quoted := make([]string, len(segments))
+	for i, segment := range segments {
+		quoted[i] = quoteSegment(segment)
+	}
+	return strings.Join(quoted, pathSeparator)
+}
+
+// SplitPath splits a path into its segments, the inverse of BuildPath: dots
+// inside backticks don't separate segments and the quotes are removed.
+func SplitPath(path string) []string {
+	segments := make([]string, 0)
+	if path == "" {
+		return segments
+	}
+	segment := strings.Builder{}
+	runes := []rune(path)
+	inQuote := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == quote && inQuote && i+1 < len(runes) && runes[i+1] == quote:
+			segment.WriteRune(quote)
+			i++
+		case r == quote:
+			inQuote = !inQuote
+		case r == '.' && !inQuote:
+			segments = append(segments, segment.String())
+			segment.Reset()
+		default:
+			segment.WriteRune(r)
+		}
+	}
+	return append(segments, segment.String())
+}
+
+func quoteSegment(segment string) string {
+	if !needsQuote(segment) {
+		return segment
+	}
+	return string(quote) + strings.Replace(segment, string(quote), string(quote)+string(quote), -1) + string(quote)
+}
+
+func needsQuote(segment string) bool {
+	if segment == "*" || segment == "**" {
+		return false
+	}
+	if segment == "" {
+		return true
+	}
+	digits := true
+	for _, r := range segment {
+		if !unicode.IsDigit(r) {
+			digits = false
+		}
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return digits
+}
@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []string
+		want     string
+	}{
+		{
+			name:     "Plain",
+			segments: []string{"root", "ln", "wf01", "temperature"},
+			want:     "root.ln.wf01.temperature",
+		}, {
+			name:     "Dot",
+			segments: []string{"root", "ln", "device.1", "temperature"},
+			want:     "root.ln.`device.1`.temperature",
+		}, {
+			name:     "Space and backtick",
+			segments: []string{"root", "ln", "my `device`"},
+			want:     "root.ln.`my ``device```",
+		}, {
+			name:     "Digits",
+			segments: []string{"root", "ln", "123"},
+			want:     "root.ln.`123`",
+		}, {
+			name:     "Wildcards",
+			segments: []string{"root", "**", "*"},
+			want:     "root.**.*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildPath(tt.segments...)
+			if got != tt.want {
+				t.Errorf("BuildPath() = %v, want %v", got, tt.want)
+			}
+			if split := SplitPath(got); !reflect.DeepEqual(split, tt.segments) {
+				t.Errorf("SplitPath() = %v, want %v", split, tt.segments)
+			}
+		})
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{
+			name: "Empty",
+			path: "",
+			want: []string{},
+		}, {
+			name: "Quoted",
+			path: "root.`a.b`.c",
+			want: []string{"root", "a.b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitPath(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
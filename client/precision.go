@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// The timestamp precisions of the server, see Config.TimePrecision.
+const (
+	TimePrecisionMillisecond = "ms"
+	TimePrecisionMicrosecond = "us"
+	TimePrecisionNanosecond  = "ns"
+)
+
+// precisionUnit returns the duration of one unit of a timestamp precision.
+func precisionUnit(precision string) (time.Duration, error) {
+	switch precision {
+	case TimePrecisionMillisecond:
+		return time.Millisecond, nil
+	case TimePrecisionMicrosecond:
+		return time.Microsecond, nil
+	case TimePrecisionNanosecond:
+		return time.Nanosecond, nil
+	default:
+		return 0, fmt.Errorf("illegal time precision %q, use ms, us or ns", precision)
+	}
+}
+
+// initTimePrecision sets the unit of the timestamps read by the data sets,
+// from the config or else from the server properties. Servers which don't
+// report it are assumed to use milliseconds.
+func (s *Session) initTimePrecision() error {
+	precision := s.config.TimePrecision
+	if precision == "" {
+		properties, err := s.client.GetProperties(context.Background())
+		if err != nil || properties == nil {
+			s.logf("can't get the time precision of the server, using ms: %v", err)
+			precision = TimePrecisionMillisecond
+		} else {
+			precision = properties.GetTimestampPrecision()
+		}
+	}
+	unit, err := precisionUnit(precision)
+	if err != nil {
+		return err
+	}
+	s.config.TimePrecision = precision
+	s.timeUnit = unit
+	return nil
+}
@@ -0,0 +1,241 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Expression is a column of a SELECT built with Column, the aggregations, Udf
+// and the arithmetic functions. Errors, such as an illegal name, are kept and
+// reported by Query.Build.
+type Expression struct {
+	text      string
+	aggregate bool
+	column    bool
+	err       error
+}
+
+func (e Expression) String() string {
+	return e.text
+}
+
+// Column is a measurement, or a path suffix, of the devices of the query.
+func Column(measurement string) Expression {
+	segments := SplitPath(measurement)
+	if len(segments) == 0 {
+		return Expression{err: errors.New("empty measurement")}
+	}
+	return Expression{text: BuildPath(segments...), column: true}
+}
+
+// Aggregation applies a built-in aggregation function to a column, prefer the
+// helpers such as Avg.
+func Aggregation(function string, column Expression) Expression {
+	if column.err != nil {
+		return column
+	}
+	if !isFunctionName(function) {
+		return Expression{err: fmt.Errorf("illegal aggregation function %s", function)}
+	}
+	if !column.column {
+		return Expression{err: fmt.Errorf("%s can only aggregate a column, got %s", function, column.text)}
+	}
+	return Expression{text: fmt.Sprintf("%s(%s)", function, column.text), aggregate: true}
+}
+
+// The built-in aggregations.
+func Avg(column Expression) Expression        { return Aggregation("avg", column) }
+func Count(column Expression) Expression      { return Aggregation("count", column) }
+func Sum(column Expression) Expression        { return Aggregation("sum", column) }
+func MinValue(column Expression) Expression   { return Aggregation("min_value", column) }
+func MaxValue(column Expression) Expression   { return Aggregation("max_value", column) }
+func FirstValue(column Expression) Expression { return Aggregation("first_value", column) }
+func LastValue(column Expression) Expression  { return Aggregation("last_value", column) }
+
+// Udf invokes a user defined function, registered with CREATE FUNCTION, on
+// columns or other expressions.
+func Udf(name string, args ...Expression) Expression {
+	if !isFunctionName(name) {
+		return Expression{err: fmt.Errorf("illegal function name %s", name)}
+	}
+	if len(args) == 0 {
+		return Expression{err: fmt.Errorf("function %s needs an argument", name)}
+	}
+	texts := make([]string, len(args))
+	for i, arg := range args {
+		if arg.err != nil {
+			return arg
+		}
+		if arg.aggregate {
+			return Expression{err: fmt.Errorf("function %s can't take the aggregation %s", name, arg.text)}
+		}
+		texts[i] = arg.text
+	}
+	return Expression{text: fmt.Sprintf("%s(%s)", name, strings.Join(texts, ", "))}
+}
+
+// The arithmetic operators, which can be nested.
+func Add(left, right Expression) Expression { return arithmetic("+", left, right) }
+func Sub(left, right Expression) Expression { return arithmetic("-", left, right) }
+func Mul(left, right Expression) Expression { return arithmetic("*", left, right) }
+func Div(left, right Expression) Expression { return arithmetic("/", left, right) }
+
+func arithmetic(operator string, left, right Expression) Expression {
+	for _, operand := range []Expression{left, right} {
+		if operand.err != nil {
+			return operand
+		}
+		if operand.aggregate {
+			return Expression{err: fmt.Errorf("%s can't take the aggregation %s", operator, operand.text)}
+		}
+	}
+	return Expression{text: fmt.Sprintf("(%s %s %s)", left.text, operator, right.text)}
+}
+
+// Condition is a WHERE clause of a query.
+type Condition struct {
+	text string
+	err  error
+}
+
+// TimeRange selects the rows in [startTime, endTime).
+func TimeRange(startTime, endTime int64) Condition {
+	if endTime <= startTime {
+		return Condition{err: errors.New("endTime must be greater than startTime")}
+	}
+	return Condition{text: fmt.Sprintf("time >= %d and time < %d", startTime, endTime)}
+}
+
+// Query is a SELECT statement built from expressions, run it with
+// Session.ExecuteQuery or get its text with Build.
+type Query struct {
+	expressions []Expression
+	devices     []string
+	where       *Condition
+	groupBy     string
+	err         error
+}
+
+// Select starts a query of the given expressions.
+func Select(expressions ...Expression) *Query {
+	return &Query{expressions: expressions}
+}
+
+// From sets the devices, or path prefixes, the columns are relative to.
+func (q *Query) From(devices ...string) *Query {
+	q.devices = devices
+	return q
+}
+
+// Where filters the rows of the query.
+func (q *Query) Where(condition Condition) *Query {
+	q.where = &condition
+	return q
+}
+
+// GroupBy aggregates [startTime, endTime) in windows of interval, all the
+// expressions must be aggregations. The interval is sent with its unit and
+// converted by the server to its time precision.
+func (q *Query) GroupBy(startTime, endTime int64, interval time.Duration) *Query {
+	if interval <= 0 {
+		q.err = errors.New("interval must be positive")
+	} else if endTime <= startTime {
+		q.err = errors.New("endTime must be greater than startTime")
+	}
+	q.groupBy = fmt.Sprintf("([%d, %d), %s)", startTime, endTime, durationLiteral(interval))
+	return q
+}
+
+// Build validates the query and returns its statement.
+func (q *Query) Build() (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	if len(q.expressions) == 0 {
+		return "", errors.New("no expression selected")
+	}
+	if len(q.devices) == 0 {
+		return "", errors.New("no device to select from")
+	}
+	texts := make([]string, len(q.expressions))
+	aggregations := 0
+	for i, expression := range q.expressions {
+		if expression.err != nil {
+			return "", expression.err
+		}
+		if expression.aggregate {
+			aggregations++
+		}
+		texts[i] = expression.text
+	}
+	if aggregations != 0 && aggregations != len(q.expressions) {
+		return "", errors.New("aggregations can't be selected with other expressions")
+	}
+	if q.groupBy != "" && aggregations == 0 {
+		return "", errors.New("group by needs aggregations")
+	}
+	devices := make([]string, len(q.devices))
+	for i, device := range q.devices {
+		segments := SplitPath(device)
+		if len(segments) == 0 {
+			return "", errors.New("empty device")
+		}
+		devices[i] = BuildPath(segments...)
+	}
+
+	sql := fmt.Sprintf("select %s from %s", strings.Join(texts, ", "), strings.Join(devices, ", "))
+	if q.where != nil {
+		if q.where.err != nil {
+			return "", q.where.err
+		}
+		sql += " where " + q.where.text
+	}
+	if q.groupBy != "" {
+		sql += " group by " + q.groupBy
+	}
+	return sql, nil
+}
+
+// ExecuteQuery builds the query and executes it, a failed statement is returned
+// as an error.
+func (s *Session) ExecuteQuery(query *Query) (*SessionDataSet, error) {
+	sql, err := query.Build()
+	if err != nil {
+		return nil, err
+	}
+	return s.executeQuery(sql)
+}
+
+// durationLiteral formats a group by interval in milliseconds, or in a finer
+// unit when it isn't a whole number of them.
+func durationLiteral(d time.Duration) string {
+	switch {
+	case d%time.Millisecond == 0:
+		return fmt.Sprintf("%dms", d/time.Millisecond)
+	case d%time.Microsecond == 0:
+		return fmt.Sprintf("%dus", d/time.Microsecond)
+	default:
+		return fmt.Sprintf("%dns", d)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuery_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *Query
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Aggregations",
+			query: Select(Avg(Column("temperature")), Count(Column("status"))).From("root.ln.wf01.wt01").Where(TimeRange(0, 100)),
+			want:  "select avg(temperature), count(status) from root.ln.wf01.wt01 where time >= 0 and time < 100",
+		}, {
+			name:  "Group by",
+			query: Select(MaxValue(Column("temperature"))).From("root.ln.wf01.wt01").GroupBy(0, 100, 10*time.Millisecond),
+			want:  "select max_value(temperature) from root.ln.wf01.wt01 group by ([0, 100), 10ms)",
+		}, {
+			name:  "Group by seconds",
+			query: Select(Avg(Column("temperature"))).From("root.ln.wf01.wt01").GroupBy(0, 100, 2*time.Second),
+			want:  "select avg(temperature) from root.ln.wf01.wt01 group by ([0, 100), 2000ms)",
+		}, {
+			name:  "Nested UDF",
+			query: Select(Udf("sin", Add(Column("s1"), Column("s2"))), Column("s1")).From("root.sg.d1", "root.sg.`d.2`"),
+			want:  "select sin((s1 + s2)), s1 from root.sg.d1, root.sg.`d.2`",
+		}, {
+			name:    "Mixed aggregation",
+			query:   Select(Avg(Column("s1")), Column("s2")).From("root.sg.d1"),
+			wantErr: true,
+		}, {
+			name:    "Aggregation of an expression",
+			query:   Select(Avg(Udf("sin", Column("s1")))).From("root.sg.d1"),
+			wantErr: true,
+		}, {
+			name:    "Illegal function name",
+			query:   Select(Udf("sin;drop", Column("s1"))).From("root.sg.d1"),
+			wantErr: true,
+		}, {
+			name:    "Group by without aggregation",
+			query:   Select(Column("s1")).From("root.sg.d1").GroupBy(0, 100, 10*time.Millisecond),
+			wantErr: true,
+		}, {
+			name:    "Empty time range",
+			query:   Select(Column("s1")).From("root.sg.d1").Where(TimeRange(100, 100)),
+			wantErr: true,
+		}, {
+			name:    "No device",
+			query:   Select(Column("s1")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Query.Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Query.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
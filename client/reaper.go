@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// dataSetRegistry holds the data sets tracked by a session. The data sets
+// untrack themselves through it and their id, it doesn't reference the session
+// so that an abandoned session stays collectable.
+type dataSetRegistry struct {
+	mutex    sync.Mutex
+	lastId   int64
+	dataSets map[int64]*SessionDataSet
+}
+
+func (r *dataSetRegistry) add(dataSet *SessionDataSet) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.lastId++
+	dataSet.registry = r
+	dataSet.registryId = r.lastId
+	r.dataSets[r.lastId] = dataSet
+}
+
+func (r *dataSetRegistry) remove(id int64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.dataSets, id)
+}
+
+func (r *dataSetRegistry) len() int {
+	if r == nil {
+		return 0
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.dataSets)
+}
+
+// untrack removes a data set from the idle data set reaping.
+func (s *SessionDataSet) untrack() {
+	if s.registry != nil {
+		s.registry.remove(s.registryId)
+		s.registry = nil
+	}
+}
+
+func (s *Session) trackDataSet(dataSet *SessionDataSet) {
+	if s.config.DataSetIdleTimeout <= 0 {
+		return
+	}
+	if s.dataSets == nil {
+		s.dataSets = &dataSetRegistry{dataSets: make(map[int64]*SessionDataSet)}
+	}
+	dataSet.clock = s.clock()
+	atomic.StoreInt64(&dataSet.lastAccess, dataSet.clock.Now().UnixNano())
+	s.dataSets.add(dataSet)
+}
+
+// ReapIdleDataSets closes the data sets of the session which were not read for
+// longer than Config.DataSetIdleTimeout, which frees their queries on the
+// server, and returns how many were closed. It is a safety net for data sets
+// abandoned without Close, not a replacement for it.
+//
+// The session isn't safe for concurrent use, so there is no background reaper:
+// the idle data sets are reaped whenever the session executes a new query, the
+// only time new server queries can leak, and when this method is called.
+func (s *Session) ReapIdleDataSets() int {
+	if s.config.DataSetIdleTimeout <= 0 || s.dataSets == nil {
+		return 0
+	}
+	now := s.clock().Now().UnixNano()
+	idle := make([]*SessionDataSet, 0)
+	s.dataSets.mutex.Lock()
+	for _, dataSet := range s.dataSets.dataSets {
+		if time.Duration(now-atomic.LoadInt64(&dataSet.lastAccess)) > s.config.DataSetIdleTimeout {
+			idle = append(idle, dataSet)
+		}
+	}
+	s.dataSets.mutex.Unlock()
+
+	for _, dataSet := range idle {
+		lastAccess := time.Unix(0, atomic.LoadInt64(&dataSet.lastAccess))
+		s.logf("closing data set of query %q idle since %v, it was not closed", dataSet.ioTDBRpcDataSet.sql, lastAccess)
+		if err := dataSet.Close(); err != nil {
+			s.logf("failed to close idle data set: %v", err)
+		}
+	}
+	return len(idle)
+}
@@ -0,0 +1,115 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSession_ReapIdleDataSets(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		idle        time.Duration
+		wantReaped  int
+		wantTracked int
+	}{
+		{
+			name:        "Disabled",
+			timeout:     0,
+			idle:        time.Hour,
+			wantReaped:  0,
+			wantTracked: 0,
+		}, {
+			name:        "Active",
+			timeout:     time.Minute,
+			idle:        time.Second,
+			wantReaped:  0,
+			wantTracked: 1,
+		}, {
+			name:        "Idle",
+			timeout:     time.Minute,
+			idle:        time.Hour,
+			wantReaped:  1,
+			wantTracked: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			clock := NewManualClock(time.Unix(0, 0))
+			s := &Session{config: &Config{DataSetIdleTimeout: tt.timeout, Logger: logger, Clock: clock}}
+			ds := createSessionDataSet()
+			s.trackDataSet(ds)
+			clock.Advance(tt.idle)
+			if got := s.ReapIdleDataSets(); got != tt.wantReaped {
+				t.Errorf("Session.ReapIdleDataSets() = %v, want %v", got, tt.wantReaped)
+			}
+			if s.dataSets.len() != tt.wantTracked {
+				t.Errorf("Session.ReapIdleDataSets() tracked = %v, want %v", s.dataSets.len(), tt.wantTracked)
+			}
+			if ds.IsClosed() != (tt.wantReaped == 1) || len(logger.messages) != tt.wantReaped {
+				t.Errorf("Session.ReapIdleDataSets() closed = %v, logs = %v", ds.IsClosed(), logger.messages)
+			}
+		})
+	}
+}
+
+func TestSession_trackDataSetCollectable(t *testing.T) {
+	finalized := make(chan struct{})
+	ds := createSessionDataSet()
+	func() {
+		s := &Session{config: &Config{DataSetIdleTimeout: time.Minute}}
+		s.trackDataSet(ds)
+		runtime.SetFinalizer(s, func(*Session) {
+			close(finalized)
+		})
+	}()
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+		select {
+		case <-finalized:
+			ds.Close()
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.Error("a session tracking a data set was never finalized")
+}
+
+func TestSession_ReapIdleDataSets_skipsStreams(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := &Session{config: &Config{DataSetIdleTimeout: time.Minute, Logger: &recordingLogger{}, Clock: clock}}
+	ds := createSessionDataSet()
+	s.trackDataSet(ds)
+	rows, errs := ds.Stream(context.Background())
+	clock.Advance(time.Hour)
+	if got := s.ReapIdleDataSets(); got != 0 {
+		t.Errorf("Session.ReapIdleDataSets() = %v, want 0 for a streamed data set", got)
+	}
+	for range rows {
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("SessionDataSet.Stream() error = %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+// Records holds rows in the parallel slices taken by Session.InsertRecords.
+type Records struct {
+	DeviceIds    []string
+	Measurements [][]string
+	DataTypes    [][]TSDataType
+	Values       [][]interface{}
+	Timestamps   []int64
+}
+
+// ToRecords converts the tablet into one record per row, for InsertRecords or
+// InsertRecordsOfOneDevice, so that a tablet can be built first and the insert
+// RPC chosen when it is sent. The unset cells are left out of the records, and
+// the rows without any set cell are skipped. Measurements are referenced by
+// their alias when they have one, as InsertTablet does.
+func (t *Tablet) ToRecords() (*Records, error) {
+	measurements := t.getInsertMeasurements()
+	records := &Records{
+		DeviceIds:    make([]string, 0, t.rowCount),
+		Measurements: make([][]string, 0, t.rowCount),
+		DataTypes:    make([][]TSDataType, 0, t.rowCount),
+		Values:       make([][]interface{}, 0, t.rowCount),
+		Timestamps:   make([]int64, 0, t.rowCount),
+	}
+	for row := 0; row < t.rowCount; row++ {
+		rowMeasurements := make([]string, 0, len(measurements))
+		rowDataTypes := make([]TSDataType, 0, len(measurements))
+		rowValues := make([]interface{}, 0, len(measurements))
+		for columnIndex, schema := range t.measurementSchemas {
+			if !t.isSet(columnIndex, row) {
+				continue
+			}
+			value, err := t.GetValueAt(columnIndex, row)
+			if err != nil {
+				return nil, err
+			}
+			rowMeasurements = append(rowMeasurements, measurements[columnIndex])
+			rowDataTypes = append(rowDataTypes, schema.DataType)
+			rowValues = append(rowValues, value)
+		}
+		if len(rowValues) == 0 {
+			continue
+		}
+		records.DeviceIds = append(records.DeviceIds, t.deviceId)
+		records.Measurements = append(records.Measurements, rowMeasurements)
+		records.DataTypes = append(records.DataTypes, rowDataTypes)
+		records.Values = append(records.Values, rowValues)
+		records.Timestamps = append(records.Timestamps, t.timestamps[row])
+	}
+	return records, nil
+}
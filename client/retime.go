@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"math"
+)
+
+// ShiftTime adds offset to every timestamp of the tablet. The tablet is left
+// unchanged if a timestamp would overflow.
+func (t *Tablet) ShiftTime(offset int64) error {
+	for _, timestamp := range t.timestamps[:t.rowCount] {
+		if offset > 0 && timestamp > math.MaxInt64-offset || offset < 0 && timestamp < math.MinInt64-offset {
+			return fmt.Errorf("timestamp %d shifted by %d overflows", timestamp, offset)
+		}
+	}
+	for row := range t.timestamps[:t.rowCount] {
+		t.timestamps[row] += offset
+	}
+	return nil
+}
+
+// RescaleTime converts every timestamp of the tablet from one precision to
+// another, TimePrecisionMillisecond, TimePrecisionMicrosecond or
+// TimePrecisionNanosecond. Converting to a coarser precision rounds the
+// timestamps down. The tablet is left unchanged if a timestamp would overflow,
+// as a millisecond timestamp past 2262 does in nanoseconds.
+func (t *Tablet) RescaleTime(fromPrecision, toPrecision string) error {
+	from, err := precisionUnit(fromPrecision)
+	if err != nil {
+		return err
+	}
+	to, err := precisionUnit(toPrecision)
+	if err != nil {
+		return err
+	}
+	if from > to {
+		factor := int64(from / to)
+		for _, timestamp := range t.timestamps[:t.rowCount] {
+			if timestamp > math.MaxInt64/factor || timestamp < math.MinInt64/factor {
+				return fmt.Errorf("timestamp %d overflows in %s", timestamp, toPrecision)
+			}
+		}
+		for row := range t.timestamps[:t.rowCount] {
+			t.timestamps[row] *= factor
+		}
+	} else if from < to {
+		factor := int64(to / from)
+		for row, timestamp := range t.timestamps[:t.rowCount] {
+			t.timestamps[row] = floorDiv(timestamp, factor)
+		}
+	}
+	return nil
+}
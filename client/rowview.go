@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import "fmt"
+
+// RowView reads one row of a tablet in place. Unlike GetValueAt its typed
+// accessors don't box the values into interfaces, so they don't allocate,
+// which matters when validating or transforming large tablets. A RowView is
+// only a tablet and a row index, it sees the later changes of the tablet.
+type RowView struct {
+	tablet *Tablet
+	row    int
+}
+
+// Row returns a view of a row of the tablet.
+func (t *Tablet) Row(rowIndex int) RowView {
+	return RowView{tablet: t, row: rowIndex}
+}
+
+// Index returns the index of the row in the tablet.
+func (r RowView) Index() int {
+	return r.row
+}
+
+// Timestamp returns the timestamp of the row.
+func (r RowView) Timestamp() (int64, error) {
+	if r.row < 0 || r.row >= r.tablet.rowCount {
+		return 0, fmt.Errorf("Illegal argument rowIndex %d", r.row)
+	}
+	return r.tablet.timestamps[r.row], nil
+}
+
+// IsNull reports whether the cell of the column wasn't set.
+func (r RowView) IsNull(columnIndex int) (bool, error) {
+	if err := r.check(columnIndex); err != nil {
+		return false, err
+	}
+	return !r.tablet.isSet(columnIndex, r.row), nil
+}
+
+// Bool returns the cell of a BOOLEAN column.
+func (r RowView) Bool(columnIndex int) (bool, error) {
+	if err := r.checkType(columnIndex, BOOLEAN); err != nil {
+		return false, err
+	}
+	return r.tablet.values[columnIndex].([]bool)[r.row], nil
+}
+
+// Int64 returns the cell of an INT32 or INT64 column.
+func (r RowView) Int64(columnIndex int) (int64, error) {
+	if err := r.checkType(columnIndex, INT32, INT64); err != nil {
+		return 0, err
+	}
+	if values, ok := r.tablet.values[columnIndex].([]int32); ok {
+		return int64(values[r.row]), nil
+	}
+	return r.tablet.values[columnIndex].([]int64)[r.row], nil
+}
+
+// Float64 returns the cell of a FLOAT or DOUBLE column.
+func (r RowView) Float64(columnIndex int) (float64, error) {
+	if err := r.checkType(columnIndex, FLOAT, DOUBLE); err != nil {
+		return 0, err
+	}
+	if values, ok := r.tablet.values[columnIndex].([]float32); ok {
+		return float64(values[r.row]), nil
+	}
+	return r.tablet.values[columnIndex].([]float64)[r.row], nil
+}
+
+// Text returns the cell of a TEXT column.
+func (r RowView) Text(columnIndex int) (string, error) {
+	if err := r.checkType(columnIndex, TEXT); err != nil {
+		return "", err
+	}
+	return r.tablet.values[columnIndex].([]string)[r.row], nil
+}
+
+func (r RowView) check(columnIndex int) error {
+	if columnIndex < 0 || columnIndex >= len(r.tablet.measurementSchemas) {
+		return fmt.Errorf("Illegal argument columnIndex %d", columnIndex)
+	}
+	if r.row < 0 || r.row >= r.tablet.rowCount {
+		return fmt.Errorf("Illegal argument rowIndex %d", r.row)
+	}
+	return nil
+}
+
+func (r RowView) checkType(columnIndex int, dataTypes ...TSDataType) error {
+	if err := r.check(columnIndex); err != nil {
+		return err
+	}
+	dataType := r.tablet.measurementSchemas[columnIndex].DataType
+	for _, t := range dataTypes {
+		if t == dataType {
+			return nil
+		}
+	}
+	return fmt.Errorf("column %s is %s", r.tablet.measurementSchemas[columnIndex].Measurement, dataTypeName(dataType))
+}
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import "testing"
+
+func TestRowView(t *testing.T) {
+	tablet, _ := createTablet(2)
+	tablet.SetTimestamp(10, 1)
+	tablet.SetValueAt(int32(3), 0, 1)
+	tablet.SetValueAt(2.5, 1, 1)
+	tablet.SetValueAt(int64(7), 2, 1)
+	tablet.SetValueAt("text", 4, 1)
+	tablet.SetValueAt(true, 5, 1)
+	row := tablet.Row(1)
+
+	if got, err := row.Timestamp(); err != nil || got != 10 {
+		t.Errorf("RowView.Timestamp() = %v, %v, want 10", got, err)
+	}
+	if got, err := row.Int64(0); err != nil || got != 3 {
+		t.Errorf("RowView.Int64() of INT32 = %v, %v, want 3", got, err)
+	}
+	if got, err := row.Int64(2); err != nil || got != 7 {
+		t.Errorf("RowView.Int64() = %v, %v, want 7", got, err)
+	}
+	if got, err := row.Float64(1); err != nil || got != 2.5 {
+		t.Errorf("RowView.Float64() = %v, %v, want 2.5", got, err)
+	}
+	if got, err := row.Text(4); err != nil || got != "text" {
+		t.Errorf("RowView.Text() = %v, %v, want text", got, err)
+	}
+	if got, err := row.Bool(5); err != nil || !got {
+		t.Errorf("RowView.Bool() = %v, %v, want true", got, err)
+	}
+	if got, err := row.IsNull(3); err != nil || !got {
+		t.Errorf("RowView.IsNull() of an unset cell = %v, %v, want true", got, err)
+	}
+	if got, err := row.IsNull(0); err != nil || got {
+		t.Errorf("RowView.IsNull() of a set cell = %v, %v, want false", got, err)
+	}
+	if _, err := row.Text(0); err == nil {
+		t.Error("RowView.Text() of an INT32 column returned no error")
+	}
+	if _, err := row.Int64(len(tablet.measurementSchemas)); err == nil {
+		t.Error("RowView.Int64() of a missing column returned no error")
+	}
+	if _, err := tablet.Row(2).Timestamp(); err == nil {
+		t.Error("RowView.Timestamp() of a missing row returned no error")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		row.Int64(2)
+		row.Float64(1)
+		row.Text(4)
+		row.IsNull(3)
+	})
+	if allocs != 0 {
+		t.Errorf("RowView accessors allocated %v times", allocs)
+	}
+}
@@ -67,6 +67,14 @@ type IoTDBRpcDataSet struct {
 	emptyResultSet             bool
 	ignoreTimeStamp            bool
 	closed                     bool
+	rowsFetched                int64
+	rowCountKnown              bool
+	// timeUnit is the duration of one unit of the timestamps, milliseconds if
+	// it is 0.
+	timeUnit time.Duration
+	// coerceTypes enables the widening of the values read, see
+	// Config.CoerceTypes.
+	coerceTypes bool
 }
 
 func (s *IoTDBRpcDataSet) getColumnIndex(columnName string) int32 {
@@ -151,12 +159,20 @@ func (s *IoTDBRpcDataSet) GetTimestamp() int64 {
 	return bytesToInt64(s.time)
 }
 
+func (s *IoTDBRpcDataSet) getTime() time.Time {
+	unit := s.timeUnit
+	if unit == 0 {
+		unit = time.Millisecond
+	}
+	return time.Unix(0, bytesToInt64(s.time)*int64(unit))
+}
+
 func (s *IoTDBRpcDataSet) getText(columnName string) string {
 	if s.closed {
 		return ""
 	}
 	if columnName == TimestampColumnName {
-		return time.Unix(0, bytesToInt64(s.time)*1000000).Format(time.RFC3339)
+		return s.getTime().Format(time.RFC3339)
 	}
 
 	columnIndex := s.getColumnIndex(columnName)
@@ -280,6 +296,14 @@ func (s *IoTDBRpcDataSet) scan(dest ...interface{}) error {
 		dataType := s.getColumnType(columnName)
 		d := dest[i]
 		valueBytes := s.values[columnIndex]
+		if s.coerceTypes {
+			if coerced, err := scanCoerced(dataType, valueBytes, d); coerced || err != nil {
+				if err != nil {
+					return fmt.Errorf("dest[%d]: %v", i, err)
+				}
+				continue
+			}
+		}
 		switch dataType {
 		case BOOLEAN:
 			switch t := d.(type) {
@@ -349,6 +373,40 @@ func (s *IoTDBRpcDataSet) scan(dest ...interface{}) error {
 	return nil
 }
 
+// maxExactFloat64 bounds the integers a float64 represents exactly.
+const maxExactFloat64 = 1 << 53
+
+// scanCoerced stores a value into a dest of a wider type than its column:
+// INT32 into *int64 or *float64, FLOAT into *float64, and INT64 into *float64
+// when the value is exact. It reports whether it stored the value, a lossy
+// conversion is an error.
+func scanCoerced(dataType TSDataType, valueBytes []byte, dest interface{}) (bool, error) {
+	switch t := dest.(type) {
+	case *int64:
+		if dataType == INT32 {
+			*t = int64(bytesToInt32(valueBytes))
+			return true, nil
+		}
+	case *float64:
+		switch dataType {
+		case INT32:
+			*t = float64(bytesToInt32(valueBytes))
+			return true, nil
+		case FLOAT:
+			*t = float64(math.Float32frombits(binary.BigEndian.Uint32(valueBytes)))
+			return true, nil
+		case INT64:
+			v := bytesToInt64(valueBytes)
+			if v > maxExactFloat64 || v < -maxExactFloat64 {
+				return false, fmt.Errorf("INT64 value %d can't be converted to float64 exactly", v)
+			}
+			*t = float64(v)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (s *IoTDBRpcDataSet) getFloat(columnName string) float32 {
 	if s.closed {
 		return 0
@@ -371,6 +429,14 @@ func (s *IoTDBRpcDataSet) getDouble(columnName string) float64 {
 
 	if !s.isNull(int(columnIndex), s.rowsIndex-1) {
 		s.lastReadWasNull = false
+		if s.coerceTypes {
+			switch s.columnTypeDeduplicatedList[columnIndex] {
+			case INT32:
+				return float64(bytesToInt32(s.values[columnIndex]))
+			case FLOAT:
+				return float64(math.Float32frombits(binary.BigEndian.Uint32(s.values[columnIndex])))
+			}
+		}
 		bits := binary.BigEndian.Uint64(s.values[columnIndex])
 		return math.Float64frombits(bits)
 	}
@@ -405,6 +471,9 @@ func (s *IoTDBRpcDataSet) getInt64(columnName string) int64 {
 
 	if !s.isNull(int(columnIndex), s.rowsIndex-1) {
 		s.lastReadWasNull = false
+		if s.coerceTypes && s.columnTypeDeduplicatedList[columnIndex] == INT32 {
+			return int64(bytesToInt32(bys))
+		}
 		return bytesToInt64(bys)
 	}
 	s.lastReadWasNull = true
@@ -424,7 +493,9 @@ func (s *IoTDBRpcDataSet) next() (bool, error) {
 	}
 
 	if s.hasCachedResults() {
-		s.constructOneRow()
+		if err := s.constructOneRow(); err != nil {
+			return false, err
+		}
 		return true, nil
 	}
 	if s.emptyResultSet {
@@ -432,8 +503,13 @@ func (s *IoTDBRpcDataSet) next() (bool, error) {
 	}
 
 	r, err := s.fetchResults()
-	if err == nil && r {
-		s.constructOneRow()
+	if err != nil {
+		return false, err
+	}
+	if r {
+		if err := s.constructOneRow(); err != nil {
+			return false, err
+		}
 		return true, nil
 	}
 	return false, nil
@@ -444,7 +520,13 @@ func (s *IoTDBRpcDataSet) fetchResults() (bool, error) {
 		return false, errClosed
 	}
 	s.rowsIndex = 0
-	req := rpc.TSFetchResultsReq{s.sessionId, s.sql, s.fetchSize, s.queryId, true}
+	req := rpc.TSFetchResultsReq{
+		SessionId: s.sessionId,
+		Statement: s.sql,
+		FetchSize: s.fetchSize,
+		QueryId:   s.queryId,
+		IsAlign:   true,
+	}
 	resp, err := s.client.FetchResults(context.Background(), &req)
 
 	if err != nil {
@@ -457,12 +539,35 @@ func (s *IoTDBRpcDataSet) fetchResults() (bool, error) {
 
 	if !resp.HasResultSet {
 		s.emptyResultSet = true
+		s.rowCountKnown = true
 	} else {
 		s.queryDataSet = resp.GetQueryDataSet()
+		s.countFetchedRows()
 	}
 	return resp.HasResultSet, nil
 }
 
+// countFetchedRows adds the rows of the current batch to rowsFetched. A batch
+// smaller than fetchSize is the last one the server will send, so the total
+// becomes known at that point.
+func (s *IoTDBRpcDataSet) countFetchedRows() {
+	if s.queryDataSet == nil {
+		return
+	}
+	rows := int64(len(s.queryDataSet.Time) / 8)
+	s.rowsFetched += rows
+	if rows < int64(s.fetchSize) {
+		s.rowCountKnown = true
+	}
+}
+
+func (s *IoTDBRpcDataSet) getTotalRowCount() (int64, bool) {
+	if s.closed || !s.rowCountKnown {
+		return -1, false
+	}
+	return s.rowsFetched, true
+}
+
 func (s *IoTDBRpcDataSet) IsClosed() bool {
 	return s.closed
 }
@@ -522,6 +627,7 @@ func NewIoTDBRpcDataSet(sql string, columnNameList []string, columnTypes []strin
 		columnCount:     len(columnNameList),
 		closed:          false,
 	}
+	ds.countFetchedRows()
 
 	ds.columnTypeList = make([]TSDataType, 0)
 
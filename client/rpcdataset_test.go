@@ -617,3 +617,178 @@ func TestIoTDBRpcDataSet_Close(t *testing.T) {
 		})
 	}
 }
+
+func TestIoTDBRpcDataSet_getTotalRowCount(t *testing.T) {
+	closedDataSet := createIoTDBRpcDataSet()
+	closedDataSet.Close()
+	partialDataSet := createIoTDBRpcDataSet()
+	partialDataSet.rowsFetched = 0
+	partialDataSet.rowCountKnown = false
+	partialDataSet.fetchSize = 5
+	partialDataSet.countFetchedRows()
+	tests := []struct {
+		name      string
+		dataSet   *IoTDBRpcDataSet
+		want      int64
+		wantKnown bool
+	}{
+		{
+			name:      "Complete",
+			dataSet:   createIoTDBRpcDataSet(),
+			want:      5,
+			wantKnown: true,
+		}, {
+			name:      "MoreBatches",
+			dataSet:   partialDataSet,
+			want:      -1,
+			wantKnown: false,
+		}, {
+			name:      "Closed",
+			dataSet:   closedDataSet,
+			want:      -1,
+			wantKnown: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, known := tt.dataSet.getTotalRowCount()
+			if got != tt.want || known != tt.wantKnown {
+				t.Errorf("IoTDBRpcDataSet.getTotalRowCount() = %v, %v, want %v, %v", got, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestIoTDBRpcDataSet_timePrecision(t *testing.T) {
+	const timestamp int64 = 1607237683018123456
+	tests := []struct {
+		name     string
+		timeUnit time.Duration
+		inserted int64
+		want     time.Time
+	}{
+		{
+			name:     "Nanoseconds",
+			timeUnit: time.Nanosecond,
+			inserted: timestamp,
+			want:     time.Unix(0, timestamp),
+		}, {
+			name:     "Microseconds",
+			timeUnit: time.Microsecond,
+			inserted: timestamp / 1000,
+			want:     time.Unix(0, timestamp/1000*1000),
+		}, {
+			name:     "Default milliseconds",
+			timeUnit: 0,
+			inserted: timestamp / 1000000,
+			want:     time.Unix(0, timestamp/1000000*1000000),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryDataSet := rpc.TSQueryDataSet{
+				Time:       int64ToBytes(tt.inserted),
+				ValueList:  [][]byte{int64ToBytes(1)},
+				BitmapList: [][]byte{{128}},
+			}
+			ds := NewIoTDBRpcDataSet("select s1 from root.sg.d1", []string{"root.sg.d1.s1"}, []string{"INT64"}, nil, 1, nil, 1, &queryDataSet, false, DefaultFetchSize)
+			ds.timeUnit = tt.timeUnit
+			if hasNext, err := ds.next(); !hasNext || err != nil {
+				t.Fatalf("IoTDBRpcDataSet.next() = %v, %v", hasNext, err)
+			}
+			if got := ds.GetTimestamp(); got != tt.inserted {
+				t.Errorf("IoTDBRpcDataSet.GetTimestamp() = %v, want %v", got, tt.inserted)
+			}
+			if got := ds.getTime(); !got.Equal(tt.want) {
+				t.Errorf("IoTDBRpcDataSet.getTime() = %v, want %v", got, tt.want)
+			}
+			if got, want := ds.getText(TimestampColumnName), tt.want.Format(time.RFC3339); got != want {
+				t.Errorf("IoTDBRpcDataSet.getText() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_precisionUnit(t *testing.T) {
+	tests := []struct {
+		precision string
+		want      time.Duration
+		wantErr   bool
+	}{
+		{TimePrecisionMillisecond, time.Millisecond, false},
+		{TimePrecisionMicrosecond, time.Microsecond, false},
+		{TimePrecisionNanosecond, time.Nanosecond, false},
+		{"s", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.precision, func(t *testing.T) {
+			got, err := precisionUnit(tt.precision)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("precisionUnit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("precisionUnit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIoTDBRpcDataSet_coerceTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		coerce  bool
+		wantErr bool
+	}{
+		{
+			name:    "Coerce",
+			coerce:  true,
+			wantErr: false,
+		}, {
+			name:    "Strict",
+			coerce:  false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := createIoTDBRpcDataSet()
+			ds.coerceTypes = tt.coerce
+			if hasNext, err := ds.next(); !hasNext || err != nil {
+				t.Fatalf("IoTDBRpcDataSet.next() = %v, %v", hasNext, err)
+			}
+			var (
+				restartCount int64
+				price        float64
+				tickCount    float64
+				temperature  float64
+			)
+			err := ds.scan(&restartCount, &price, &tickCount, &temperature)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IoTDBRpcDataSet.scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if restartCount != 1 || price != 1988.2 || tickCount != 3333333 || temperature != float64(float32(12.1)) {
+				t.Errorf("IoTDBRpcDataSet.scan() = %v, %v, %v, %v", restartCount, price, tickCount, temperature)
+			}
+			if got := ds.getInt64("root.ln.device1.restart_count"); got != 1 {
+				t.Errorf("IoTDBRpcDataSet.getInt64() of INT32 = %v, want 1", got)
+			}
+			if got := ds.getDouble("root.ln.device1.temperature"); got != float64(float32(12.1)) {
+				t.Errorf("IoTDBRpcDataSet.getDouble() of FLOAT = %v, want %v", got, float64(float32(12.1)))
+			}
+		})
+	}
+}
+
+func Test_scanCoerced(t *testing.T) {
+	var f float64
+	if _, err := scanCoerced(INT64, int64ToBytes(1<<60), &f); err == nil {
+		t.Error("scanCoerced() accepted a lossy INT64 to float64 conversion")
+	}
+	var s string
+	if coerced, err := scanCoerced(INT32, int32ToBytes(1), &s); coerced || err != nil {
+		t.Errorf("scanCoerced() = %v, %v for a *string dest", coerced, err)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNullScalar is returned by the typed scalar queries when the cell is null.
+var ErrNullScalar = errors.New("the scalar is null")
+
+// QueryScalar executes a query returning a single row of a single column,
+// such as select count(s1) from root.sg.d1, and returns its value: a bool,
+// int32, int64, float32, float64 or string, nil if it is null. A result with
+// more rows or columns is an error.
+func (s *Session) QueryScalar(sql string) (interface{}, error) {
+	dataSet, err := s.executeQuery(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer dataSet.Close()
+	return readScalar(dataSet)
+}
+
+// QueryInt64 is QueryScalar for an INT32 or INT64 value.
+func (s *Session) QueryInt64(sql string) (int64, error) {
+	value, err := s.QueryScalar(sql)
+	if err != nil {
+		return 0, err
+	}
+	return scalarInt64(value)
+}
+
+// QueryFloat64 is QueryScalar for a numeric value, converted to float64.
+func (s *Session) QueryFloat64(sql string) (float64, error) {
+	value, err := s.QueryScalar(sql)
+	if err != nil {
+		return 0, err
+	}
+	return scalarFloat64(value)
+}
+
+// QueryString is QueryScalar for a TEXT value.
+func (s *Session) QueryString(sql string) (string, error) {
+	value, err := s.QueryScalar(sql)
+	if err != nil {
+		return "", err
+	}
+	return scalarString(value)
+}
+
+func readScalar(dataSet *SessionDataSet) (interface{}, error) {
+	if dataSet.GetColumnCount() != 1 {
+		return nil, fmt.Errorf("expected one column, got %d", dataSet.GetColumnCount())
+	}
+	hasNext, err := dataSet.Next()
+	if err != nil {
+		return nil, err
+	}
+	if !hasNext {
+		return nil, errors.New("expected one row, got none")
+	}
+	value := dataSet.GetValue(dataSet.GetColumnName(0))
+	if hasNext, err = dataSet.Next(); err != nil {
+		return nil, err
+	}
+	if hasNext {
+		return nil, errors.New("expected one row, got more")
+	}
+	return value, nil
+}
+
+func scalarInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int32:
+		return int64(v), nil
+	case nil:
+		return 0, ErrNullScalar
+	default:
+		return 0, fmt.Errorf("the scalar is %T, not an integer", value)
+	}
+}
+
+func scalarFloat64(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case nil:
+		return 0, ErrNullScalar
+	default:
+		return 0, fmt.Errorf("the scalar is %T, not a number", value)
+	}
+}
+
+func scalarString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", ErrNullScalar
+	default:
+		return "", fmt.Errorf("the scalar is %T, not a string", value)
+	}
+}
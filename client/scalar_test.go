@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func Test_readScalar(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		types   []string
+		data    rpc.TSQueryDataSet
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Count",
+			columns: []string{"count(root.sg.d1.s1)"},
+			types:   []string{"INT64"},
+			data: rpc.TSQueryDataSet{
+				Time:       make([]byte, 8),
+				ValueList:  [][]byte{int64ToBytes(42)},
+				BitmapList: [][]byte{{128}},
+			},
+			want: int64(42),
+		}, {
+			name:    "Null",
+			columns: []string{"last_value(root.sg.d1.s1)"},
+			types:   []string{"INT32"},
+			data: rpc.TSQueryDataSet{
+				Time:       make([]byte, 8),
+				ValueList:  [][]byte{{}},
+				BitmapList: [][]byte{{0}},
+			},
+			want: nil,
+		}, {
+			name:    "No row",
+			columns: []string{"root.sg.d1.s1"},
+			types:   []string{"INT32"},
+			data:    rpc.TSQueryDataSet{},
+			wantErr: true,
+		}, {
+			name:    "Two rows",
+			columns: []string{"root.sg.d1.s1"},
+			types:   []string{"INT32"},
+			data: rpc.TSQueryDataSet{
+				Time:       make([]byte, 16),
+				ValueList:  [][]byte{append(int32ToBytes(1), int32ToBytes(2)...)},
+				BitmapList: [][]byte{{192}},
+			},
+			wantErr: true,
+		}, {
+			name:    "Two columns",
+			columns: []string{"root.sg.d1.s1", "root.sg.d1.s2"},
+			types:   []string{"INT32", "INT32"},
+			data: rpc.TSQueryDataSet{
+				Time:       make([]byte, 8),
+				ValueList:  [][]byte{int32ToBytes(1), int32ToBytes(2)},
+				BitmapList: [][]byte{{128}, {128}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := NewSessionDataSet("", tt.columns, tt.types, nil, 1, nil, 1, &tt.data, true, DefaultFetchSize)
+			ds.ioTDBRpcDataSet.emptyResultSet = true
+			got, err := readScalar(ds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readScalar() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("readScalar() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_scalarConversions(t *testing.T) {
+	if got, err := scalarInt64(int32(7)); got != 7 || err != nil {
+		t.Errorf("scalarInt64(int32) = %v, %v", got, err)
+	}
+	if _, err := scalarInt64(1.5); err == nil {
+		t.Error("scalarInt64(float64) didn't fail")
+	}
+	if _, err := scalarInt64(nil); err != ErrNullScalar {
+		t.Errorf("scalarInt64(nil) error = %v, want %v", err, ErrNullScalar)
+	}
+	if got, err := scalarFloat64(float32(0.5)); got != 0.5 || err != nil {
+		t.Errorf("scalarFloat64(float32) = %v, %v", got, err)
+	}
+	if got, err := scalarFloat64(int64(3)); got != 3 || err != nil {
+		t.Errorf("scalarFloat64(int64) = %v, %v", got, err)
+	}
+	if _, err := scalarFloat64("3"); err == nil {
+		t.Error("scalarFloat64(string) didn't fail")
+	}
+	if got, err := scalarString("on"); got != "on" || err != nil {
+		t.Errorf("scalarString(string) = %v, %v", got, err)
+	}
+	if _, err := scalarString(true); err == nil {
+		t.Error("scalarString(bool) didn't fail")
+	}
+}
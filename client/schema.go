@@ -0,0 +1,247 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// schemaMatrix lists the encodings a server release accepts for each data type
+// and the compressors it accepts.
+type schemaMatrix struct {
+	major, minor int
+	encodings    map[TSDataType][]TSEncoding
+	compressors  []TSCompressionType
+}
+
+// schemaMatrices are ordered by release. The first one also applies to the
+// older servers, the last one to the newer servers and to an unknown version.
+var schemaMatrices = []schemaMatrix{
+	{
+		major: 0,
+		minor: 12,
+		encodings: map[TSDataType][]TSEncoding{
+			BOOLEAN: {PLAIN, RLE},
+			INT32:   {PLAIN, RLE, TS_2DIFF, REGULAR, GORILLA},
+			INT64:   {PLAIN, RLE, TS_2DIFF, REGULAR, GORILLA},
+			FLOAT:   {PLAIN, RLE, TS_2DIFF, GORILLA},
+			DOUBLE:  {PLAIN, RLE, TS_2DIFF, GORILLA},
+			TEXT:    {PLAIN},
+		},
+		compressors: []TSCompressionType{UNCOMPRESSED, SNAPPY, GZIP, LZ4},
+	}, {
+		major: 0,
+		minor: 13,
+		encodings: map[TSDataType][]TSEncoding{
+			BOOLEAN: {PLAIN, RLE},
+			INT32:   {PLAIN, RLE, TS_2DIFF, REGULAR, GORILLA_V1, GORILLA},
+			INT64:   {PLAIN, RLE, TS_2DIFF, REGULAR, GORILLA_V1, GORILLA},
+			FLOAT:   {PLAIN, RLE, TS_2DIFF, GORILLA_V1, GORILLA},
+			DOUBLE:  {PLAIN, RLE, TS_2DIFF, GORILLA_V1, GORILLA},
+			TEXT:    {PLAIN, PLAIN_DICTIONARY},
+		},
+		compressors: []TSCompressionType{UNCOMPRESSED, SNAPPY, GZIP, LZO, SDT, PAA, PLA, LZ4},
+	},
+}
+
+// matrixOf returns the schema matrix of a server version, such as 0.12.4, the
+// one of the latest release if the version is empty or illegal.
+func matrixOf(version string) schemaMatrix {
+	major, minor, err := parseServerVersion(version)
+	if err != nil {
+		return schemaMatrices[len(schemaMatrices)-1]
+	}
+	matrix := schemaMatrices[0]
+	for _, m := range schemaMatrices {
+		if major > m.major || major == m.major && minor >= m.minor {
+			matrix = m
+		}
+	}
+	return matrix
+}
+
+// SupportedEncodings returns a copy of the encodings a server version accepts
+// for a data type, nil for an unknown data type. An empty version stands for
+// the latest release known to the client.
+func SupportedEncodings(version string, dataType TSDataType) []TSEncoding {
+	encodings, exists := matrixOf(version).encodings[dataType]
+	if !exists {
+		return nil
+	}
+	return append([]TSEncoding(nil), encodings...)
+}
+
+// SupportedCompressors returns a copy of the compressors a server version
+// accepts, see SupportedEncodings for the version.
+func SupportedCompressors(version string) []TSCompressionType {
+	return append([]TSCompressionType(nil), matrixOf(version).compressors...)
+}
+
+// ValidateSchema checks that the combination of data type, encoding and
+// compressor is accepted by the latest server release known to the client,
+// see ValidateSchemaForVersion.
+func ValidateSchema(dataType TSDataType, encoding TSEncoding, compressor TSCompressionType) error {
+	return ValidateSchemaForVersion("", dataType, encoding, compressor)
+}
+
+// ValidateSchemaForVersion checks that the combination of data type, encoding
+// and compressor is accepted by a server version, according to
+// SupportedEncodings and SupportedCompressors.
+func ValidateSchemaForVersion(version string, dataType TSDataType, encoding TSEncoding, compressor TSCompressionType) error {
+	matrix := matrixOf(version)
+	encodings, exists := matrix.encodings[dataType]
+	if !exists {
+		return fmt.Errorf("unsupported data type %v", dataType)
+	}
+	supported := false
+	for _, e := range encodings {
+		if e == encoding {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("encoding %v is not supported for data type %s, use one of %v", encoding, dataTypeName(dataType), encodings)
+	}
+	for _, c := range matrix.compressors {
+		if c == compressor {
+			return nil
+		}
+	}
+	return fmt.Errorf("compressor %v is not supported, use one of %v", compressor, matrix.compressors)
+}
+
+// validateSchema checks a schema to create with ValidateSchemaForVersion and
+// the version of the server when Config.ValidateSchemas is set.
+func (s *Session) validateSchema(dataType TSDataType, encoding TSEncoding, compressor TSCompressionType) error {
+	if !s.config.ValidateSchemas {
+		return nil
+	}
+	if s.serverVersion == "" {
+		properties, err := s.client.GetProperties(context.Background())
+		if err != nil || properties == nil {
+			s.logf("can't get the version of the server, validating the schema for the latest release: %v", err)
+		} else {
+			s.serverVersion = properties.GetVersion()
+		}
+	}
+	return ValidateSchemaForVersion(s.serverVersion, dataType, encoding, compressor)
+}
+
+// NewMeasurementSchema creates a measurement schema after checking its data
+// type, encoding and compressor with ValidateSchema.
+func NewMeasurementSchema(measurement string, dataType TSDataType, encoding TSEncoding, compressor TSCompressionType) (*MeasurementSchema, error) {
+	if err := ValidateSchema(dataType, encoding, compressor); err != nil {
+		return nil, fmt.Errorf("measurement %s: %v", measurement, err)
+	}
+	return &MeasurementSchema{
+		Measurement: measurement,
+		DataType:    dataType,
+		Encoding:    encoding,
+		Compressor:  compressor,
+	}, nil
+}
+
+// lowCardinalityRatio is the highest ratio of distinct values to values for
+// which SuggestEncoding prefers RLE.
+const lowCardinalityRatio = 0.1
+
+// ColumnStats describes the set cells of a tablet column, see
+// Tablet.ColumnStats.
+type ColumnStats struct {
+	Count    int
+	Distinct int
+	// Monotonic is true if the values never decrease in time order, it is
+	// only computed for INT32 and INT64 columns.
+	Monotonic bool
+}
+
+// SuggestEncoding picks an encoding for a column from its statistics: RLE for
+// booleans and low cardinality numbers, TS_2DIFF for monotonic integers,
+// GORILLA for floating point numbers and PLAIN otherwise.
+func SuggestEncoding(stats ColumnStats, dataType TSDataType) TSEncoding {
+	switch dataType {
+	case BOOLEAN:
+		return RLE
+	case INT32, INT64, FLOAT, DOUBLE:
+		if stats.Count > 0 && float64(stats.Distinct) <= lowCardinalityRatio*float64(stats.Count) {
+			return RLE
+		}
+		if dataType == FLOAT || dataType == DOUBLE {
+			return GORILLA
+		}
+		if stats.Monotonic && stats.Count > 0 {
+			return TS_2DIFF
+		}
+	}
+	return PLAIN
+}
+
+// ColumnStats computes the statistics of the set cells of a column.
+func (t *Tablet) ColumnStats(columnIndex int) (ColumnStats, error) {
+	if columnIndex < 0 || columnIndex >= len(t.measurementSchemas) {
+		return ColumnStats{}, fmt.Errorf("column index %d out of range", columnIndex)
+	}
+	dataType := t.measurementSchemas[columnIndex].DataType
+	stats := ColumnStats{Monotonic: dataType == INT32 || dataType == INT64}
+	distinct := make(map[interface{}]struct{})
+	var previous int64
+	for _, row := range sortedIndex(t.timestamps[:t.rowCount]) {
+		if !t.isSet(columnIndex, row) {
+			continue
+		}
+		value, err := t.GetValueAt(columnIndex, row)
+		if err != nil {
+			return ColumnStats{}, err
+		}
+		distinct[value] = struct{}{}
+		if stats.Monotonic {
+			var current int64
+			if v, ok := value.(int32); ok {
+				current = int64(v)
+			} else {
+				current = value.(int64)
+			}
+			if stats.Count > 0 && current < previous {
+				stats.Monotonic = false
+			}
+			previous = current
+		}
+		stats.Count++
+	}
+	stats.Distinct = len(distinct)
+	return stats, nil
+}
+
+// SuggestEncodings suggests an encoding for every column of the tablet with
+// SuggestEncoding, in the order of its measurement schemas, for example to
+// create the time series with CreateMultiTimeseries.
+func (t *Tablet) SuggestEncodings() ([]TSEncoding, error) {
+	encodings := make([]TSEncoding, len(t.measurementSchemas))
+	for i, schema := range t.measurementSchemas {
+		stats, err := t.ColumnStats(i)
+		if err != nil {
+			return nil, err
+		}
+		encodings[i] = SuggestEncoding(stats, schema.DataType)
+	}
+	return encodings, nil
+}
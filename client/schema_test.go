@@ -0,0 +1,264 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func TestValidateSchemaForVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		dataType   TSDataType
+		encoding   TSEncoding
+		compressor TSCompressionType
+		wantErr    bool
+	}{
+		{
+			name:       "BOOLEAN RLE",
+			version:    "0.12.4",
+			dataType:   BOOLEAN,
+			encoding:   RLE,
+			compressor: SNAPPY,
+			wantErr:    false,
+		}, {
+			name:       "BOOLEAN TS_2DIFF",
+			version:    "0.12.4",
+			dataType:   BOOLEAN,
+			encoding:   TS_2DIFF,
+			compressor: SNAPPY,
+			wantErr:    true,
+		}, {
+			name:       "DOUBLE GORILLA",
+			version:    "0.12.4",
+			dataType:   DOUBLE,
+			encoding:   GORILLA,
+			compressor: LZ4,
+			wantErr:    false,
+		}, {
+			name:       "TEXT RLE",
+			version:    "0.13.0",
+			dataType:   TEXT,
+			encoding:   RLE,
+			compressor: UNCOMPRESSED,
+			wantErr:    true,
+		}, {
+			name:       "INT64 SDT on 0.12",
+			version:    "0.12.4",
+			dataType:   INT64,
+			encoding:   TS_2DIFF,
+			compressor: SDT,
+			wantErr:    true,
+		}, {
+			name:       "INT64 SDT on 0.13",
+			version:    "0.13.0-SNAPSHOT",
+			dataType:   INT64,
+			encoding:   TS_2DIFF,
+			compressor: SDT,
+			wantErr:    false,
+		}, {
+			name:       "TEXT dictionary on 0.12",
+			version:    "0.12.4",
+			dataType:   TEXT,
+			encoding:   PLAIN_DICTIONARY,
+			compressor: SNAPPY,
+			wantErr:    true,
+		}, {
+			name:       "TEXT dictionary on a newer release",
+			version:    "1.0.0",
+			dataType:   TEXT,
+			encoding:   PLAIN_DICTIONARY,
+			compressor: SNAPPY,
+			wantErr:    false,
+		}, {
+			name:       "GORILLA_V1 on an unknown version",
+			version:    "",
+			dataType:   FLOAT,
+			encoding:   GORILLA_V1,
+			compressor: LZO,
+			wantErr:    false,
+		}, {
+			name:       "Unknown data type",
+			dataType:   UNKNOW,
+			encoding:   PLAIN,
+			compressor: UNCOMPRESSED,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSchemaForVersion(tt.version, tt.dataType, tt.encoding, tt.compressor); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSchemaForVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			wantErr := ValidateSchema(tt.dataType, tt.encoding, tt.compressor) != nil
+			if _, err := NewMeasurementSchema("s1", tt.dataType, tt.encoding, tt.compressor); (err != nil) != wantErr {
+				t.Errorf("NewMeasurementSchema() error = %v, wantErr %v", err, wantErr)
+			}
+		})
+	}
+}
+
+func TestSupportedEncodings(t *testing.T) {
+	encodings := SupportedEncodings("0.12.4", TEXT)
+	encodings[0] = GORILLA
+	if got := SupportedEncodings("0.12.4", TEXT); !reflect.DeepEqual(got, []TSEncoding{PLAIN}) {
+		t.Errorf("SupportedEncodings() = %v after changing a returned slice", got)
+	}
+	compressors := SupportedCompressors("0.12.4")
+	compressors[0] = LZ4
+	if got := SupportedCompressors("0.12.4"); got[0] != UNCOMPRESSED {
+		t.Errorf("SupportedCompressors() = %v after changing a returned slice", got)
+	}
+	if got := SupportedEncodings("0.11.2", TEXT); !reflect.DeepEqual(got, []TSEncoding{PLAIN}) {
+		t.Errorf("SupportedEncodings() of an older server = %v, want those of 0.12", got)
+	}
+}
+
+type propertiesClient struct {
+	version string
+}
+
+func (c *propertiesClient) Call(ctx context.Context, method string, args, result thrift.TStruct) error {
+	if r, ok := result.(*rpc.TSIServiceGetPropertiesResult); ok {
+		r.Success = &rpc.ServerProperties{Version: c.version}
+	}
+	return nil
+}
+
+func TestSession_validateSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate bool
+		client   thrift.TClient
+		wantErr  bool
+	}{
+		{
+			name:     "Disabled",
+			validate: false,
+			client:   &propertiesClient{version: "0.12.4"},
+			wantErr:  false,
+		}, {
+			name:     "Older server",
+			validate: true,
+			client:   &propertiesClient{version: "0.12.4"},
+			wantErr:  true,
+		}, {
+			name:     "Newer server",
+			validate: true,
+			client:   &propertiesClient{version: "0.13.1"},
+			wantErr:  false,
+		}, {
+			name:     "Unknown version",
+			validate: true,
+			client:   &failingClient{err: errors.New("connection reset by peer")},
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Session{config: &Config{ValidateSchemas: tt.validate, Logger: &recordingLogger{}}, client: rpc.NewTSIServiceClient(tt.client)}
+			if err := s.validateSchema(TEXT, PLAIN_DICTIONARY, SNAPPY); (err != nil) != tt.wantErr {
+				t.Errorf("Session.validateSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSuggestEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    ColumnStats
+		dataType TSDataType
+		want     TSEncoding
+	}{
+		{
+			name:     "Low cardinality",
+			stats:    ColumnStats{Count: 100, Distinct: 3},
+			dataType: INT32,
+			want:     RLE,
+		}, {
+			name:     "Monotonic",
+			stats:    ColumnStats{Count: 100, Distinct: 100, Monotonic: true},
+			dataType: INT64,
+			want:     TS_2DIFF,
+		}, {
+			name:     "Random integers",
+			stats:    ColumnStats{Count: 100, Distinct: 100},
+			dataType: INT64,
+			want:     PLAIN,
+		}, {
+			name:     "Floats",
+			stats:    ColumnStats{Count: 100, Distinct: 100},
+			dataType: DOUBLE,
+			want:     GORILLA,
+		}, {
+			name:     "Text",
+			stats:    ColumnStats{Count: 100, Distinct: 1},
+			dataType: TEXT,
+			want:     PLAIN,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestEncoding(tt.stats, tt.dataType); got != tt.want {
+				t.Errorf("SuggestEncoding() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTablet_SuggestEncodings(t *testing.T) {
+	rowCount := 20
+	tablet, err := createTablet(rowCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for row := 0; row < rowCount; row++ {
+		timestamp := int64(rowCount - row)
+		tablet.SetTimestamp(timestamp, row)
+		tablet.SetValueAt(int32(1), 0, row)
+		tablet.SetValueAt(float64(row%7)+float64(row)/100, 1, row)
+		tablet.SetValueAt(timestamp*10, 2, row)
+		tablet.SetValueAt("text", 4, row)
+		tablet.SetValueAt(row%2 == 0, 5, row)
+	}
+	stats, err := tablet.ColumnStats(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (ColumnStats{Count: rowCount, Distinct: rowCount, Monotonic: true}); stats != want {
+		t.Errorf("Tablet.ColumnStats() = %+v, want %+v", stats, want)
+	}
+	got, err := tablet.SuggestEncodings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TSEncoding{RLE, GORILLA, TS_2DIFF, GORILLA, PLAIN, RLE}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tablet.SuggestEncodings() = %v, want %v", got, want)
+	}
+}
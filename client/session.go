@@ -25,9 +25,12 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"log"
 	"net"
+	"os"
 	"reflect"
-	"sort"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/apache/iotdb-client-go/rpc"
@@ -39,7 +42,29 @@ const (
 	DefaultFetchSize = 1024
 )
 
-var lengthError = errors.New("deviceIds, times, measurementsList and valuesList's size should be equal")
+// TransportType selects how the thrift messages are written on the socket, it
+// must match the transport of the server.
+type TransportType int
+
+const (
+	// TransportFramed prefixes every message with its length, it is the
+	// transport of the IoTDB server by default.
+	TransportFramed TransportType = iota
+	// TransportBuffered writes the messages unframed through a buffer.
+	TransportBuffered
+)
+
+// transportBufferSize is the buffer size of TransportBuffered.
+const transportBufferSize = 8192
+
+var lengthError = errors.New("deviceIds, times, measurementsList, dataTypesList and valuesList's size should be equal")
+
+var defaultLogger Logger = log.New(os.Stderr, "[iotdb-client-go] ", log.LstdFlags)
+
+// Logger receives the warnings reported by the client, *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
 
 type Config struct {
 	Host      string
@@ -48,6 +73,62 @@ type Config struct {
 	Password  string
 	FetchSize int32
 	TimeZone  string
+	// Logger receives client warnings, they are written to stderr if it is nil.
+	Logger Logger
+	// DataSetIdleTimeout enables closing the data sets which were abandoned
+	// without Close, see Session.ReapIdleDataSets. It is disabled if it is 0.
+	DataSetIdleTimeout time.Duration
+	// AllowPartialResults makes SessionDataSet.ReadAll return the rows read
+	// before a failure along with a *PartialResultError.
+	AllowPartialResults bool
+	// DeviceIdNormalizer rewrites the device ids of all the inserts, for
+	// example NormalizeDeviceId. The ids are sent verbatim if it is nil.
+	DeviceIdNormalizer func(deviceId string) string
+	// TimePrecision is the precision of the timestamps of the server, ms, us
+	// or ns. Open asks the server for it if it is empty.
+	TimePrecision string
+	// BreakerThreshold enables a circuit breaker, which fails the calls with
+	// ErrCircuitOpen for BreakerCooldown once that many consecutive
+	// connections or RPCs failed. It is disabled if it is 0.
+	BreakerThreshold int
+	// BreakerCooldown is how long the breaker stays open before a trial call,
+	// DefaultBreakerCooldown if it is 0.
+	BreakerCooldown time.Duration
+	// ValidateNames checks the device ids and the measurements of the inserts
+	// with ValidatePath and ValidateIdentifier before sending them.
+	ValidateNames bool
+	// CoerceTypes lets the data sets widen the values read: GetInt64 reads
+	// INT32 columns, GetDouble INT32 and FLOAT columns, and Scan stores INT32
+	// into *int64 or *float64, FLOAT into *float64, and INT64 into *float64
+	// unless the value would lose precision, which is an error.
+	CoerceTypes bool
+	// TransportType is the thrift transport, TransportFramed by default. A
+	// transport which doesn't match the server makes Open fail or hang until
+	// the connection timeout.
+	TransportType TransportType
+	// SendBufferSize and RecvBufferSize set the SO_SNDBUF and SO_RCVBUF sizes
+	// of the socket, in bytes, between MinSocketBufferSize and
+	// MaxSocketBufferSize, to raise the throughput over links with a high
+	// latency. The OS defaults are kept when they are 0. The OS caps them
+	// silently, to net.core.wmem_max and net.core.rmem_max on Linux, which may
+	// have to be raised as well.
+	SendBufferSize int
+	RecvBufferSize int
+	// LogWarnings logs, through the Logger, the warnings the server attaches
+	// to successful operations, see Warnings.
+	LogWarnings bool
+	// CaptureInserts keeps the last CaptureInserts insert requests, with their
+	// serialized values, for LastInserts and ReplayInsert. Each one holds its
+	// whole payload, so it should stay small, 0 disables the capture.
+	CaptureInserts int
+	// Clock is the time source of the idle data set reaping, the coalescers
+	// and the circuit breaker, the system clock if it is nil.
+	Clock Clock
+	// ValidateSchemas checks the data types, encodings and compressors of
+	// CreateTimeseries and CreateMultiTimeseries with ValidateSchemaForVersion
+	// and the version of the server before sending them. The server is left
+	// to reject the combinations it doesn't support if it is false.
+	ValidateSchemas bool
 }
 
 type Session struct {
@@ -57,6 +138,12 @@ type Session struct {
 	isClose            bool
 	trans              thrift.TTransport
 	requestStatementId int64
+	dataSets           *dataSetRegistry
+	systemStatus       SystemStatus
+	timeUnit           time.Duration
+	breaker            *circuitBreaker
+	capture            *insertCapture
+	serverVersion      string
 }
 
 func (s *Session) Open(enableRPCCompression bool, connectionTimeoutInMs int) error {
@@ -67,18 +154,39 @@ func (s *Session) Open(enableRPCCompression bool, connectionTimeoutInMs int) err
 		s.config.TimeZone = DefaultTimeZone
 	}
 
+	if s.config.BreakerThreshold > 0 && s.breaker == nil {
+		s.breaker = newCircuitBreaker(s.config.BreakerThreshold, s.config.BreakerCooldown, s.clock(), s.config.logf)
+	}
+	if s.breaker != nil && !s.breaker.allow() {
+		return ErrCircuitOpen
+	}
+
+	if err := checkSocketBufferSize(s.config.SendBufferSize); err != nil {
+		return err
+	}
+	if err := checkSocketBufferSize(s.config.RecvBufferSize); err != nil {
+		return err
+	}
+
 	var protocolFactory thrift.TProtocolFactory
-	var err error
-	s.trans, err = thrift.NewTSocketTimeout(net.JoinHostPort(s.config.Host, s.config.Port), time.Duration(connectionTimeoutInMs))
+	socket, err := thrift.NewTSocketTimeout(net.JoinHostPort(s.config.Host, s.config.Port), time.Duration(connectionTimeoutInMs))
+	if err == nil {
+		s.trans, err = wrapTransport(socket, s.config.TransportType)
+	}
+	if err == nil {
+		if !s.trans.IsOpen() {
+			err = s.trans.Open()
+		}
+	}
+	if s.breaker != nil {
+		s.breaker.record(err)
+	}
 	if err != nil {
 		return err
 	}
-	s.trans = thrift.NewTFramedTransport(s.trans)
-	if !s.trans.IsOpen() {
-		err = s.trans.Open()
-		if err != nil {
-			return err
-		}
+	if err = setSocketBufferSizes(socket.Conn(), s.config.SendBufferSize, s.config.RecvBufferSize); err != nil {
+		s.trans.Close()
+		return err
 	}
 	if enableRPCCompression {
 		protocolFactory = thrift.NewTCompactProtocolFactory()
@@ -87,12 +195,25 @@ func (s *Session) Open(enableRPCCompression bool, connectionTimeoutInMs int) err
 	}
 	iprot := protocolFactory.GetProtocol(s.trans)
 	oprot := protocolFactory.GetProtocol(s.trans)
-	s.client = rpc.NewTSIServiceClient(thrift.NewTStandardClient(iprot, oprot))
+	var client thrift.TClient = thrift.NewTStandardClient(iprot, oprot)
+	if s.breaker != nil {
+		client = &breakerClient{client: client, breaker: s.breaker}
+	}
+	if s.config.LogWarnings {
+		client = &warningClient{client: client, logf: s.config.logf}
+	}
+	if s.config.CaptureInserts > 0 {
+		if s.capture == nil {
+			s.capture = newInsertCapture(s.config.CaptureInserts)
+		}
+		client = &captureClient{client: client, capture: s.capture, clock: s.clock()}
+	}
+	s.client = rpc.NewTSIServiceClient(client)
 	req := rpc.TSOpenSessionReq{ClientProtocol: rpc.TSProtocolVersion_IOTDB_SERVICE_PROTOCOL_V3, ZoneId: s.config.TimeZone, Username: &s.config.UserName,
 		Password: &s.config.Password}
 	resp, err := s.client.OpenSession(context.Background(), &req)
 	if err != nil {
-		return err
+		return fmt.Errorf("open session with the %s transport, check that the server uses it: %w", transportName(s.config.TransportType), err)
 	}
 	s.sessionId = resp.GetSessionId()
 	s.requestStatementId, err = s.client.RequestStatementId(context.Background(), s.sessionId)
@@ -100,6 +221,10 @@ func (s *Session) Open(enableRPCCompression bool, connectionTimeoutInMs int) err
 		return err
 	}
 
+	if err = s.initTimePrecision(); err != nil {
+		return err
+	}
+
 	s.SetTimeZone(s.config.TimeZone)
 	s.config.TimeZone, err = s.GetTimeZone()
 	return err
@@ -112,9 +237,74 @@ func (s *Session) Close() (r *rpc.TSStatus, err error) {
 	if err != nil {
 		return nil, err
 	}
+	s.isClose = true
 	return nil, s.trans.Close()
 }
 
+func wrapTransport(trans thrift.TTransport, transportType TransportType) (thrift.TTransport, error) {
+	switch transportType {
+	case TransportFramed:
+		return thrift.NewTFramedTransport(trans), nil
+	case TransportBuffered:
+		return thrift.NewTBufferedTransport(trans, transportBufferSize), nil
+	default:
+		return nil, fmt.Errorf("illegal transport type %d", transportType)
+	}
+}
+
+func transportName(transportType TransportType) string {
+	if transportType == TransportBuffered {
+		return "buffered"
+	}
+	return "framed"
+}
+
+// normalizeDeviceId applies the DeviceIdNormalizer of the config and logs the
+// ids it changes.
+func (s *Session) normalizeDeviceId(deviceId string) string {
+	if s.config.DeviceIdNormalizer == nil {
+		return deviceId
+	}
+	normalized := s.config.DeviceIdNormalizer(deviceId)
+	if normalized != deviceId {
+		s.logf("device id %s normalized to %s", deviceId, normalized)
+	}
+	return normalized
+}
+
+// NormalizeDeviceId is a DeviceIdNormalizer which lowercases the id and removes
+// its trailing dots, so that root.SG.Dev1. and root.sg.dev1 are the same device.
+func NormalizeDeviceId(deviceId string) string {
+	return strings.TrimRight(strings.ToLower(deviceId), ".")
+}
+
+func (s *Session) logf(format string, v ...interface{}) {
+	s.config.logf(format, v...)
+}
+
+// logf writes to the Logger of the config. The values the session owns, such
+// as its circuit breaker, log through it rather than through the session, so
+// they don't reference the session and keep its finalizer from running.
+func (c *Config) logf(format string, v ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, v...)
+	} else {
+		defaultLogger.Printf(format, v...)
+	}
+}
+
+// finalizeSession is a safety net closing the transport of a session which was
+// garbage collected without being closed, so a forgotten session doesn't hold a
+// server connection slot forever. Callers must not rely on it: the server side
+// session is not closed, and the finalizer may run late or never.
+func finalizeSession(s *Session) {
+	if s.isClose || s.trans == nil || !s.trans.IsOpen() {
+		return
+	}
+	s.logf("session %d to %s:%s was garbage collected without Close, closing its transport", s.sessionId, s.config.Host, s.config.Port)
+	s.trans.Close()
+}
+
 /*
  *set one storage group
  *param
@@ -162,6 +352,9 @@ func (s *Session) DeleteStorageGroups(storageGroupIds ...string) (r *rpc.TSStatu
  *error: correctness of operation
  */
 func (s *Session) CreateTimeseries(path string, dataType TSDataType, encoding TSEncoding, compressor TSCompressionType, attributes map[string]string, tags map[string]string) (r *rpc.TSStatus, err error) {
+	if err := s.validateSchema(dataType, encoding, compressor); err != nil {
+		return nil, err
+	}
 	request := rpc.TSCreateTimeseriesReq{SessionId: s.sessionId, Path: path, DataType: int32(dataType), Encoding: int32(encoding),
 		Compressor: int32(compressor), Attributes: attributes, Tags: tags}
 	status, err := s.client.CreateTimeseries(context.Background(), &request)
@@ -179,6 +372,15 @@ func (s *Session) CreateTimeseries(path string, dataType TSDataType, encoding TS
  *error: correctness of operation
  */
 func (s *Session) CreateMultiTimeseries(paths []string, dataTypes []TSDataType, encodings []TSEncoding, compressors []TSCompressionType) (r *rpc.TSStatus, err error) {
+	if len(dataTypes) != len(paths) || len(encodings) != len(paths) || len(compressors) != len(paths) {
+		return nil, errors.New("paths, dataTypes, encodings and compressors's size should be equal")
+	}
+	for i := range paths {
+		if err := s.validateSchema(dataTypes[i], encodings[i], compressors[i]); err != nil {
+			return nil, fmt.Errorf("paths[%d] %s: %v", i, paths[i], err)
+		}
+	}
+
 	destTypes := make([]int32, len(dataTypes))
 	for i, t := range dataTypes {
 		destTypes[i] = int32(t)
@@ -239,6 +441,13 @@ func (s *Session) DeleteData(paths []string, startTime int64, endTime int64) (r
  *error: correctness of operation
  */
 func (s *Session) InsertStringRecord(deviceId string, measurements []string, values []string, timestamp int64) (r *rpc.TSStatus, err error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	deviceId = s.normalizeDeviceId(deviceId)
+	if err := s.checkNames(deviceId, measurements); err != nil {
+		return nil, err
+	}
 	request := rpc.TSInsertStringRecordReq{SessionId: s.sessionId, DeviceId: deviceId, Measurements: measurements,
 		Values: values, Timestamp: timestamp}
 	r, err = s.client.InsertStringRecord(context.Background(), &request)
@@ -268,23 +477,183 @@ func (s *Session) ExecuteStatement(sql string) (*SessionDataSet, error) {
 		FetchSize:   &s.config.FetchSize,
 	}
 	resp, err := s.client.ExecuteStatement(context.Background(), &request)
-	return s.genDataSet(sql, resp), err
+	return s.toDataSet(sql, resp, err)
+}
+
+// ExecuteNonQueryStatement executes a statement which doesn't return a result
+// set, such as DDL or DML statements, and returns its status.
+func (s *Session) ExecuteNonQueryStatement(sql string) (r *rpc.TSStatus, err error) {
+	request := rpc.TSExecuteStatementReq{
+		SessionId:   s.sessionId,
+		Statement:   sql,
+		StatementId: s.requestStatementId,
+		FetchSize:   &s.config.FetchSize,
+	}
+	resp, err := s.client.ExecuteStatement(context.Background(), &request)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
 }
 
 func (s *Session) ExecuteQueryStatement(sql string) (*SessionDataSet, error) {
 	request := rpc.TSExecuteStatementReq{SessionId: s.sessionId, Statement: sql, StatementId: s.requestStatementId,
 		FetchSize: &s.config.FetchSize}
-	if resp, err := s.client.ExecuteQueryStatement(context.Background(), &request); err == nil {
-		return NewSessionDataSet(sql, resp.Columns, resp.DataTypeList, resp.ColumnNameIndexMap, *resp.QueryId, s.client, s.sessionId, resp.QueryDataSet, resp.IgnoreTimeStamp != nil && *resp.IgnoreTimeStamp, s.config.FetchSize), err
-	} else {
+	resp, err := s.client.ExecuteQueryStatement(context.Background(), &request)
+	return s.toDataSet(sql, resp, err)
+}
+
+// executeQuery is ExecuteQueryStatement for the helpers reading the result, a
+// statement without a result set is an error.
+func (s *Session) executeQuery(sql string) (*SessionDataSet, error) {
+	dataSet, err := s.ExecuteQueryStatement(sql)
+	if err == nil && dataSet == nil {
+		return nil, fmt.Errorf("statement %s returned no result set", sql)
+	}
+	return dataSet, err
+}
+
+// ExecuteQueryStatementDesc executes a query returning its rows newest first.
+// ORDER BY TIME DESC is added to sql when it has no ORDER BY clause, before its
+// LIMIT, OFFSET, SLIMIT, SOFFSET, FILL, ALIGN BY DEVICE or DISABLE ALIGN
+// clauses, so the server scans in descending time order and the data set reads
+// the latest rows first, without any reversal on the client. It needs a server
+// supporting ORDER BY TIME DESC, older ones reject the statement with an SQL
+// parse error.
+func (s *Session) ExecuteQueryStatementDesc(sql string) (*SessionDataSet, error) {
+	return s.executeQuery(descendingStatement(sql))
+}
+
+// orderByFollowers are the clauses which come after ORDER BY in a query.
+var orderByFollowers = [][]string{{"limit"}, {"offset"}, {"slimit"}, {"soffset"}, {"fill"},
+	{"align", "by", "device"}, {"disable", "align"}}
+
+func descendingStatement(sql string) string {
+	words := sqlWords(sql)
+	if clauseIndex(words, []string{"order", "by"}) >= 0 {
+		return sql
+	}
+	sql = strings.TrimRight(strings.TrimSpace(sql), ";")
+	at := len(sql)
+	for _, clause := range orderByFollowers {
+		if i := clauseIndex(words, clause); i >= 0 && words[i].start < at {
+			at = words[i].start
+		}
+	}
+	if at == len(sql) {
+		return sql + " order by time desc"
+	}
+	return strings.TrimSpace(sql[:at]) + " order by time desc " + sql[at:]
+}
+
+type sqlWord struct {
+	text  string
+	start int
+}
+
+// sqlWords returns the lower case words of sql, skipping the quoted strings
+// and names.
+func sqlWords(sql string) []sqlWord {
+	words := make([]sqlWord, 0)
+	var quote byte
+	start := -1
+	for i := 0; i <= len(sql); i++ {
+		var c byte
+		if i < len(sql) {
+			c = sql[i]
+		}
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		isWordByte := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if isWordByte && start < 0 {
+			start = i
+		} else if !isWordByte && start >= 0 {
+			words = append(words, sqlWord{text: strings.ToLower(sql[start:i]), start: start})
+			start = -1
+		}
+		if c == '\'' || c == '"' || c == '`' {
+			quote = c
+		}
+	}
+	return words
+}
+
+// clauseIndex returns the index of the first word of clause in words, or -1.
+func clauseIndex(words []sqlWord, clause []string) int {
+	for i := 0; i+len(clause) <= len(words); i++ {
+		matches := true
+		for j, word := range clause {
+			if words[i+j].text != word {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return i
+		}
+	}
+	return -1
+}
+
+// ColumnSchema is the name and data type of a result column.
+type ColumnSchema struct {
+	Name     string
+	DataType TSDataType
+}
+
+// GetQueryColumns returns the result columns of a query without reading its
+// rows: the query is executed with a fetch size of 1, so at most one row is
+// transferred, and closed right away.
+func (s *Session) GetQueryColumns(sql string) ([]ColumnSchema, error) {
+	var fetchSize int32 = 1
+	request := rpc.TSExecuteStatementReq{SessionId: s.sessionId, Statement: sql, StatementId: s.requestStatementId,
+		FetchSize: &fetchSize}
+	resp, err := s.client.ExecuteQueryStatement(context.Background(), &request)
+	if err != nil {
+		return nil, err
+	}
+	if err = VerifySuccess(resp.Status); err != nil {
 		return nil, err
 	}
+	if resp.QueryId != nil {
+		closeRequest := &rpc.TSCloseOperationReq{SessionId: s.sessionId, QueryId: resp.QueryId}
+		if _, err = s.client.CloseOperation(context.Background(), closeRequest); err != nil {
+			return nil, err
+		}
+	}
+	return columnSchemas(resp.Columns, resp.DataTypeList)
+}
+
+func columnSchemas(columns []string, dataTypes []string) ([]ColumnSchema, error) {
+	if len(dataTypes) != len(columns) {
+		return nil, fmt.Errorf("the server returned %d data types for %d columns", len(dataTypes), len(columns))
+	}
+	schemas := make([]ColumnSchema, len(columns))
+	for i, name := range columns {
+		dataType, exists := tsTypeMap[dataTypes[i]]
+		if !exists {
+			dataType = UNKNOW
+		}
+		schemas[i] = ColumnSchema{Name: name, DataType: dataType}
+	}
+	return schemas, nil
 }
 
 func (s *Session) genTSInsertRecordReq(deviceId string, time int64,
 	measurements []string,
 	types []TSDataType,
 	values []interface{}) (*rpc.TSInsertRecordReq, error) {
+	deviceId = s.normalizeDeviceId(deviceId)
+	if err := s.checkNames(deviceId, measurements); err != nil {
+		return nil, err
+	}
+	if err := checkRecord(measurements, types, values); err != nil {
+		return nil, err
+	}
 	request := &rpc.TSInsertRecordReq{}
 	request.SessionId = s.sessionId
 	request.DeviceId = deviceId
@@ -300,6 +669,9 @@ func (s *Session) genTSInsertRecordReq(deviceId string, time int64,
 }
 
 func (s *Session) InsertRecord(deviceId string, measurements []string, dataTypes []TSDataType, values []interface{}, timestamp int64) (r *rpc.TSStatus, err error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 	request, err := s.genTSInsertRecordReq(deviceId, timestamp, measurements, dataTypes, values)
 	if err != nil {
 		return nil, err
@@ -313,23 +685,38 @@ func (s *Session) InsertRecord(deviceId string, measurements []string, dataTypes
 // your performance, please see insertTablet method
 // Each row is independent, which could have different deviceId, time, number of measurements
 func (s *Session) InsertRecordsOfOneDevice(deviceId string, timestamps []int64, measurementsSlice [][]string, dataTypesSlice [][]TSDataType, valuesSlice [][]interface{}, sorted bool) (r *rpc.TSStatus, err error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 	length := len(timestamps)
 	if len(measurementsSlice) != length || len(dataTypesSlice) != length || len(valuesSlice) != length {
 		return nil, errors.New("timestamps, measurementsSlice and valuesSlice's size should be equal")
 	}
 
 	if !sorted {
-		sortFunc := func(i, j int) bool {
-			return timestamps[i] < timestamps[j]
+		index := sortedIndex(timestamps)
+		orderedTimestamps := make([]int64, length)
+		orderedMeasurements := make([][]string, length)
+		orderedDataTypes := make([][]TSDataType, length)
+		orderedValues := make([][]interface{}, length)
+		for i, from := range index {
+			orderedTimestamps[i] = timestamps[from]
+			orderedMeasurements[i] = measurementsSlice[from]
+			orderedDataTypes[i] = dataTypesSlice[from]
+			orderedValues[i] = valuesSlice[from]
 		}
-		sort.Slice(measurementsSlice, sortFunc)
-		sort.Slice(dataTypesSlice, sortFunc)
-		sort.Slice(valuesSlice, sortFunc)
-		sort.Slice(timestamps, sortFunc)
+		timestamps, measurementsSlice, dataTypesSlice, valuesSlice = orderedTimestamps, orderedMeasurements, orderedDataTypes, orderedValues
 	}
 
+	deviceId = s.normalizeDeviceId(deviceId)
 	valuesList := make([][]byte, length)
 	for i := 0; i < length; i++ {
+		if err := s.checkNames(deviceId, measurementsSlice[i]); err != nil {
+			return nil, err
+		}
+		if err := checkRecord(measurementsSlice[i], dataTypesSlice[i], valuesSlice[i]); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i, err)
+		}
 		if valuesList[i], err = valuesToBytes(dataTypesSlice[i], valuesSlice[i]); err != nil {
 			return nil, err
 		}
@@ -359,6 +746,9 @@ func (s *Session) InsertRecordsOfOneDevice(deviceId string, timestamps []int64,
  */
 func (s *Session) InsertRecords(deviceIds []string, measurements [][]string, dataTypes [][]TSDataType, values [][]interface{},
 	timestamps []int64) (r *rpc.TSStatus, err error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 	request, err := s.genInsertRecordsReq(deviceIds, measurements, dataTypes, values, timestamps)
 	if err != nil {
 		return nil, err
@@ -373,6 +763,14 @@ func (s *Session) InsertRecords(deviceIds []string, measurements [][]string, dat
  *tablets: []*client.Tablet, list of tablets
  */
 func (s *Session) InsertTablets(tablets []*Tablet, sorted bool) (r *rpc.TSStatus, err error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	for _, t := range tablets {
+		if err := t.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	if !sorted {
 		for _, t := range tablets {
 			if err := t.Sort(); err != nil {
@@ -405,7 +803,7 @@ func (s *Session) ExecuteRawDataQuery(paths []string, startTime int64, endTime i
 		StatementId: s.requestStatementId,
 	}
 	resp, err := s.client.ExecuteRawDataQuery(context.Background(), &request)
-	return s.genDataSet("", resp), err
+	return s.toDataSet("", resp, err)
 }
 
 func (s *Session) ExecuteUpdateStatement(sql string) (*SessionDataSet, error) {
@@ -416,11 +814,33 @@ func (s *Session) ExecuteUpdateStatement(sql string) (*SessionDataSet, error) {
 		FetchSize:   &s.config.FetchSize,
 	}
 	resp, err := s.client.ExecuteUpdateStatement(context.Background(), &request)
-	return s.genDataSet(sql, resp), err
+	return s.toDataSet(sql, resp, err)
+}
+
+// toDataSet returns the data set of a statement response, or an error if the
+// RPC or the statement failed. A successful statement without a result set,
+// such as DDL, returns a nil data set.
+func (s *Session) toDataSet(sql string, resp *rpc.TSExecuteStatementResp, err error) (*SessionDataSet, error) {
+	if err != nil {
+		return nil, err
+	}
+	if err = VerifySuccess(resp.Status); err != nil {
+		return nil, err
+	}
+	return s.genDataSet(sql, resp), nil
 }
 
 func (s *Session) genDataSet(sql string, resp *rpc.TSExecuteStatementResp) *SessionDataSet {
-	return NewSessionDataSet(sql, resp.Columns, resp.DataTypeList, resp.ColumnNameIndexMap, *resp.QueryId, s.client, s.sessionId, resp.QueryDataSet, resp.IgnoreTimeStamp != nil && *resp.IgnoreTimeStamp, s.config.FetchSize)
+	if resp == nil || resp.QueryId == nil {
+		return nil
+	}
+	dataSet := NewSessionDataSet(sql, resp.Columns, resp.DataTypeList, resp.ColumnNameIndexMap, *resp.QueryId, s.client, s.sessionId, resp.QueryDataSet, resp.IgnoreTimeStamp != nil && *resp.IgnoreTimeStamp, s.config.FetchSize)
+	dataSet.SetAllowPartialResult(s.config.AllowPartialResults)
+	dataSet.ioTDBRpcDataSet.timeUnit = s.timeUnit
+	dataSet.SetCoerceTypes(s.config.CoerceTypes)
+	s.ReapIdleDataSets()
+	s.trackDataSet(dataSet)
+	return dataSet
 }
 
 func (s *Session) genInsertTabletsReq(tablets []*Tablet) (*rpc.TSInsertTabletsReq, error) {
@@ -434,8 +854,11 @@ func (s *Session) genInsertTabletsReq(tablets []*Tablet) (*rpc.TSInsertTabletsRe
 		sizeList         = make([]int32, length)
 	)
 	for index, tablet := range tablets {
-		deviceIds[index] = tablet.deviceId
-		measurementsList[index] = tablet.GetMeasurements()
+		deviceIds[index] = s.normalizeDeviceId(tablet.deviceId)
+		measurementsList[index] = tablet.getInsertMeasurements()
+		if err := s.checkNames(deviceIds[index], measurementsList[index]); err != nil {
+			return nil, err
+		}
 
 		values, err := tablet.getValuesBytes()
 		if err != nil {
@@ -462,17 +885,27 @@ func (s *Session) genInsertTabletsReq(tablets []*Tablet) (*rpc.TSInsertTabletsRe
 func (s *Session) genInsertRecordsReq(deviceIds []string, measurements [][]string, dataTypes [][]TSDataType, values [][]interface{},
 	timestamps []int64) (*rpc.TSInsertRecordsReq, error) {
 	length := len(deviceIds)
-	if length != len(timestamps) || length != len(measurements) || length != len(values) {
+	if length != len(timestamps) || length != len(measurements) || length != len(dataTypes) || length != len(values) {
 		return nil, lengthError
 	}
+	normalizedDeviceIds := make([]string, length)
+	for i, deviceId := range deviceIds {
+		normalizedDeviceIds[i] = s.normalizeDeviceId(deviceId)
+		if err := s.checkNames(normalizedDeviceIds[i], measurements[i]); err != nil {
+			return nil, err
+		}
+	}
 	request := rpc.TSInsertRecordsReq{
 		SessionId:        s.sessionId,
-		DeviceIds:        deviceIds,
+		DeviceIds:        normalizedDeviceIds,
 		MeasurementsList: measurements,
 		Timestamps:       timestamps,
 	}
 	v := make([][]byte, length)
 	for i := 0; i < len(measurements); i++ {
+		if err := checkRecord(measurements[i], dataTypes[i], values[i]); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i, err)
+		}
 		if bys, err := valuesToBytes(dataTypes[i], values[i]); err == nil {
 			v[i] = bys
 		} else {
@@ -483,6 +916,42 @@ func (s *Session) genInsertRecordsReq(deviceIds []string, measurements [][]strin
 	return &request, nil
 }
 
+// checkRecord checks that measurements, dataTypes and values have the same
+// length and that every value has the Go type of its data type.
+func checkRecord(measurements []string, dataTypes []TSDataType, values []interface{}) error {
+	if len(dataTypes) != len(measurements) || len(values) != len(measurements) {
+		return fmt.Errorf("%d measurements, %d dataTypes and %d values, they should have the same size",
+			len(measurements), len(dataTypes), len(values))
+	}
+	for i, dataType := range dataTypes {
+		value := values[i]
+		if value == nil {
+			return fmt.Errorf("value at index %d is nil, dataType is %s", i, dataTypeName(dataType))
+		}
+		ok := false
+		switch dataType {
+		case BOOLEAN:
+			_, ok = value.(bool)
+		case INT32:
+			_, ok = value.(int32)
+		case INT64:
+			_, ok = value.(int64)
+		case FLOAT:
+			_, ok = value.(float32)
+		case DOUBLE:
+			_, ok = value.(float64)
+		case TEXT:
+			_, ok = value.(string)
+		default:
+			return fmt.Errorf("dataType at index %d is illegal, it must be in (BOOLEAN, INT32, INT64, FLOAT, DOUBLE, TEXT)", i)
+		}
+		if !ok {
+			return fmt.Errorf("value at index %d is %T but dataType is %s", i, value, dataTypeName(dataType))
+		}
+	}
+	return nil
+}
+
 func valuesToBytes(dataTypes []TSDataType, values []interface{}) ([]byte, error) {
 	buff := &bytes.Buffer{}
 	for i, t := range dataTypes {
@@ -546,6 +1015,12 @@ func valuesToBytes(dataTypes []TSDataType, values []interface{}) ([]byte, error)
 }
 
 func (s *Session) InsertTablet(tablet *Tablet, sorted bool) (r *rpc.TSStatus, err error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if err := tablet.Validate(); err != nil {
+		return nil, err
+	}
 	if !sorted {
 		if err := tablet.Sort(); err != nil {
 			return nil, err
@@ -558,12 +1033,133 @@ func (s *Session) InsertTablet(tablet *Tablet, sorted bool) (r *rpc.TSStatus, er
 	return s.client.InsertTablet(context.Background(), request)
 }
 
+// InsertTabletWithColumnOrder is InsertTablet sending the columns in
+// columnOrder, the indexes of the tablet columns in the order of the request,
+// see Tablet.ColumnOrder. The columns of the tablet aren't moved, so it can be
+// inserted into several targets ordered differently. Its rows are sorted
+// unless sorted is true, as with InsertTablet.
+func (s *Session) InsertTabletWithColumnOrder(tablet *Tablet, columnOrder []int, sorted bool) (r *rpc.TSStatus, err error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if err := tablet.Validate(); err != nil {
+		return nil, err
+	}
+	if !sorted {
+		if err := tablet.Sort(); err != nil {
+			return nil, err
+		}
+	}
+	ordered, err := tablet.reordered(columnOrder)
+	if err != nil {
+		return nil, err
+	}
+	request, err := s.genTSInsertTabletReq(ordered)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.InsertTablet(context.Background(), request)
+}
+
+/*
+ *insert a tablet already serialized, without a Tablet
+ *params
+ *deviceId: string, time series path for device
+ *measurements: []string, the measurements of the columns
+ *types: []int32, the TSDataType of the columns
+ *rowCount: int, the number of rows
+ *timestampBytes: []byte, the big-endian int64 timestamps, sorted in ascending order
+ *valueBytes: []byte, the columns in the format of the tablet insert request
+ *return
+ *error: correctness of operation
+ *
+ *The buffers are sent as they are, so they must follow the format of
+ *Tablet.GetTimestampBytes and of the tablet values: each column in turn, one byte per
+ *BOOLEAN, big-endian INT32, INT64, FLOAT and DOUBLE, TEXT as an int32 length followed
+ *by its bytes. Their sizes are checked against the types and the row count.
+ */
+func (s *Session) InsertTabletRaw(deviceId string, measurements []string, types []int32, rowCount int,
+	timestampBytes, valueBytes []byte) (r *rpc.TSStatus, err error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if err := checkRawTablet(measurements, types, rowCount, timestampBytes, valueBytes); err != nil {
+		return nil, err
+	}
+	deviceId = s.normalizeDeviceId(deviceId)
+	if err := s.checkNames(deviceId, measurements); err != nil {
+		return nil, err
+	}
+	request := &rpc.TSInsertTabletReq{
+		SessionId:    s.sessionId,
+		DeviceId:     deviceId,
+		Measurements: measurements,
+		Values:       valueBytes,
+		Timestamps:   timestampBytes,
+		Types:        types,
+		Size:         int32(rowCount),
+	}
+	return s.client.InsertTablet(context.Background(), request)
+}
+
+// checkRawTablet checks that the buffers of InsertTabletRaw hold rowCount rows
+// of the given types.
+func checkRawTablet(measurements []string, types []int32, rowCount int, timestampBytes, valueBytes []byte) error {
+	if len(measurements) != len(types) {
+		return errors.New("measurements and types should have the same size")
+	}
+	if rowCount <= 0 {
+		return errors.New("rowCount must be positive")
+	}
+	if len(timestampBytes) != 8*rowCount {
+		return fmt.Errorf("%d timestamp bytes for %d rows, want %d", len(timestampBytes), rowCount, 8*rowCount)
+	}
+	offset := 0
+	for i, t := range types {
+		width := 0
+		switch TSDataType(t) {
+		case BOOLEAN:
+			width = 1
+		case INT32, FLOAT:
+			width = 4
+		case INT64, DOUBLE:
+			width = 8
+		case TEXT:
+			for row := 0; row < rowCount; row++ {
+				if offset+4 > len(valueBytes) {
+					return fmt.Errorf("value bytes end in column %s", measurements[i])
+				}
+				length := int(bytesToInt32(valueBytes[offset : offset+4]))
+				if length < 0 {
+					return fmt.Errorf("negative text length in column %s", measurements[i])
+				}
+				offset += 4 + length
+			}
+		default:
+			return fmt.Errorf("Illegal datatype %v of column %s", t, measurements[i])
+		}
+		offset += width * rowCount
+		if offset > len(valueBytes) {
+			return fmt.Errorf("value bytes end in column %s", measurements[i])
+		}
+	}
+	if offset != len(valueBytes) {
+		return fmt.Errorf("%d value bytes for %d rows, want %d", len(valueBytes), rowCount, offset)
+	}
+	return nil
+}
+
 func (s *Session) genTSInsertTabletReq(tablet *Tablet) (*rpc.TSInsertTabletReq, error) {
+	deviceId := s.normalizeDeviceId(tablet.deviceId)
+	measurements := tablet.getInsertMeasurements()
+	if err := s.checkNames(deviceId, measurements); err != nil {
+		return nil, err
+	}
 	if values, err := tablet.getValuesBytes(); err == nil {
 		request := &rpc.TSInsertTabletReq{
 			SessionId:    s.sessionId,
-			DeviceId:     tablet.deviceId,
-			Measurements: tablet.GetMeasurements(),
+			DeviceId:     deviceId,
+			Measurements: measurements,
 			Values:       values,
 			Timestamps:   tablet.GetTimestampBytes(),
 			Types:        tablet.getDataTypes(),
@@ -579,6 +1175,10 @@ func (s *Session) GetSessionId() int64 {
 	return s.sessionId
 }
 
+// NewSession creates a session with the given config. The session must be
+// closed with Close once it is no longer used.
 func NewSession(config *Config) *Session {
-	return &Session{config: config}
+	session := &Session{config: config}
+	runtime.SetFinalizer(session, finalizeSession)
+	return session
 }
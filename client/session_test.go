@@ -0,0 +1,498 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/apache/iotdb-client-go/rpc"
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+type closeRecordingTransport struct {
+	*thrift.TMemoryBuffer
+	closed bool
+}
+
+func (t *closeRecordingTransport) IsOpen() bool {
+	return !t.closed
+}
+
+func (t *closeRecordingTransport) Close() error {
+	t.closed = true
+	return nil
+}
+
+func Test_finalizeSession(t *testing.T) {
+	tests := []struct {
+		name       string
+		isClose    bool
+		wantClosed bool
+		wantLogs   int
+	}{
+		{
+			name:       "Leaked",
+			isClose:    false,
+			wantClosed: true,
+			wantLogs:   1,
+		}, {
+			name:       "Closed",
+			isClose:    true,
+			wantClosed: false,
+			wantLogs:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			trans := &closeRecordingTransport{TMemoryBuffer: thrift.NewTMemoryBuffer()}
+			s := &Session{config: &Config{Host: "127.0.0.1", Port: "6667", Logger: logger}, trans: trans, isClose: tt.isClose}
+			finalizeSession(s)
+			if trans.closed != tt.wantClosed {
+				t.Errorf("finalizeSession() closed transport = %v, want %v", trans.closed, tt.wantClosed)
+			}
+			if len(logger.messages) != tt.wantLogs {
+				t.Errorf("finalizeSession() logged %v, want %d messages", logger.messages, tt.wantLogs)
+			}
+		})
+	}
+}
+
+func TestNewSession_finalizerWithBreakerAndWarnings(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	closed := make(chan struct{})
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// Never answer, read until the client closes the connection.
+		io.Copy(ioutil.Discard, conn)
+		close(closed)
+	}()
+
+	func() {
+		host, port, _ := net.SplitHostPort(listener.Addr().String())
+		s := NewSession(&Config{Host: host, Port: port, BreakerThreshold: 3, LogWarnings: true,
+			Logger: log.New(ioutil.Discard, "", 0)})
+		// The timeout is in nanoseconds, OpenSession times out waiting for the
+		// reply and leaves the transport open.
+		if err := s.Open(false, int(100*time.Millisecond)); err == nil {
+			t.Fatal("Session.Open() succeeded without a server")
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		runtime.GC()
+		select {
+		case <-closed:
+			return
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	t.Error("the finalizer didn't close the transport of a leaked session")
+}
+
+func Test_columnSchemas(t *testing.T) {
+	tests := []struct {
+		name      string
+		columns   []string
+		dataTypes []string
+		want      []ColumnSchema
+		wantErr   bool
+	}{
+		{
+			name:      "Columns",
+			columns:   []string{"root.ln.device1.restart_count", "root.ln.device1.description", "count(root.ln.device1.status)"},
+			dataTypes: []string{"INT32", "TEXT", "UNSUPPORTED"},
+			want: []ColumnSchema{
+				{Name: "root.ln.device1.restart_count", DataType: INT32},
+				{Name: "root.ln.device1.description", DataType: TEXT},
+				{Name: "count(root.ln.device1.status)", DataType: UNKNOW},
+			},
+		}, {
+			name:      "Missing data types",
+			columns:   []string{"root.ln.device1.restart_count", "root.ln.device1.description"},
+			dataTypes: []string{"INT32"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := columnSchemas(tt.columns, tt.dataTypes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("columnSchemas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("columnSchemas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_descendingStatement(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "Without order by",
+			sql:  "select * from root.ln.device1 where time > 1608268702769;",
+			want: "select * from root.ln.device1 where time > 1608268702769 order by time desc",
+		}, {
+			name: "With order by",
+			sql:  "select * from root.ln.device1 ORDER BY TIME ASC",
+			want: "select * from root.ln.device1 ORDER BY TIME ASC",
+		}, {
+			name: "Limit",
+			sql:  "select s1 from root.ln.device1 where time > 10 LIMIT 10 OFFSET 5;",
+			want: "select s1 from root.ln.device1 where time > 10 order by time desc LIMIT 10 OFFSET 5",
+		}, {
+			name: "Align by device",
+			sql:  "select * from root.ln.* slimit 2 align by device",
+			want: "select * from root.ln.* order by time desc slimit 2 align by device",
+		}, {
+			name: "Quoted clause",
+			sql:  "select * from root.ln.device1 where s1 = 'order by limit'",
+			want: "select * from root.ln.device1 where s1 = 'order by limit' order by time desc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := descendingStatement(tt.sql); got != tt.want {
+				t.Errorf("descendingStatement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSession_normalizeDeviceId(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalizer func(string) string
+		deviceId   string
+		want       string
+		wantLogs   int
+	}{
+		{
+			name:       "No normalizer",
+			normalizer: nil,
+			deviceId:   "root.SG.Dev1.",
+			want:       "root.SG.Dev1.",
+			wantLogs:   0,
+		}, {
+			name:       "Changed",
+			normalizer: NormalizeDeviceId,
+			deviceId:   "root.SG.Dev1..",
+			want:       "root.sg.dev1",
+			wantLogs:   1,
+		}, {
+			name:       "Unchanged",
+			normalizer: NormalizeDeviceId,
+			deviceId:   "root.sg.dev1",
+			want:       "root.sg.dev1",
+			wantLogs:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			s := &Session{config: &Config{Logger: logger, DeviceIdNormalizer: tt.normalizer}}
+			request, err := s.genTSInsertRecordReq(tt.deviceId, 1, []string{"s1"}, []TSDataType{INT32}, []interface{}{int32(1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if request.DeviceId != tt.want {
+				t.Errorf("genTSInsertRecordReq() DeviceId = %v, want %v", request.DeviceId, tt.want)
+			}
+			if len(logger.messages) != tt.wantLogs {
+				t.Errorf("normalizeDeviceId() logged %v, want %d messages", logger.messages, tt.wantLogs)
+			}
+		})
+	}
+}
+
+func Test_checkRawTablet(t *testing.T) {
+	tablet, _ := NewTablet("root.ln.device1", []*MeasurementSchema{
+		{Measurement: "restart_count", DataType: INT32},
+		{Measurement: "description", DataType: TEXT},
+		{Measurement: "status", DataType: BOOLEAN},
+	}, 2)
+	tablet.SetValueAt("text", 1, 0)
+	tablet.SetValueAt("", 1, 1)
+	valueBytes, _ := tablet.getValuesBytes()
+	timestampBytes := tablet.GetTimestampBytes()
+	measurements := tablet.GetMeasurements()
+	types := tablet.getDataTypes()
+
+	tests := []struct {
+		name           string
+		types          []int32
+		rowCount       int
+		timestampBytes []byte
+		valueBytes     []byte
+		wantErr        bool
+	}{
+		{
+			name:           "Tablet bytes",
+			types:          types,
+			rowCount:       2,
+			timestampBytes: timestampBytes,
+			valueBytes:     valueBytes,
+			wantErr:        false,
+		}, {
+			name:           "Missing timestamp",
+			types:          types,
+			rowCount:       2,
+			timestampBytes: timestampBytes[:8],
+			valueBytes:     valueBytes,
+			wantErr:        true,
+		}, {
+			name:           "Truncated values",
+			types:          types,
+			rowCount:       2,
+			timestampBytes: timestampBytes,
+			valueBytes:     valueBytes[:len(valueBytes)-1],
+			wantErr:        true,
+		}, {
+			name:           "Extra values",
+			types:          types,
+			rowCount:       2,
+			timestampBytes: timestampBytes,
+			valueBytes:     append(append([]byte{}, valueBytes...), 0),
+			wantErr:        true,
+		}, {
+			name:           "Types",
+			types:          types[:2],
+			rowCount:       2,
+			timestampBytes: timestampBytes,
+			valueBytes:     valueBytes,
+			wantErr:        true,
+		}, {
+			name:           "Illegal type",
+			types:          []int32{int32(INT32), int32(TEXT), 42},
+			rowCount:       2,
+			timestampBytes: timestampBytes,
+			valueBytes:     valueBytes,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkRawTablet(measurements, tt.types, tt.rowCount, tt.timestampBytes, tt.valueBytes); (err != nil) != tt.wantErr {
+				t.Errorf("checkRawTablet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_wrapTransport(t *testing.T) {
+	tests := []struct {
+		name          string
+		transportType TransportType
+		want          interface{}
+		wantErr       bool
+	}{
+		{
+			name:          "Framed",
+			transportType: TransportFramed,
+			want:          &thrift.TFramedTransport{},
+		}, {
+			name:          "Buffered",
+			transportType: TransportBuffered,
+			want:          &thrift.TBufferedTransport{},
+		}, {
+			name:          "Illegal",
+			transportType: TransportType(42),
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := wrapTransport(thrift.NewTMemoryBuffer(), tt.transportType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrapTransport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && reflect.TypeOf(got) != reflect.TypeOf(tt.want) {
+				t.Errorf("wrapTransport() = %T, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkRecord(t *testing.T) {
+	tests := []struct {
+		name         string
+		measurements []string
+		dataTypes    []TSDataType
+		values       []interface{}
+		wantErr      string
+	}{
+		{
+			name:         "Valid",
+			measurements: []string{"s1", "s2", "s3", "s4", "s5", "s6"},
+			dataTypes:    []TSDataType{BOOLEAN, INT32, INT64, FLOAT, DOUBLE, TEXT},
+			values:       []interface{}{true, int32(1), int64(2), float32(3), 4.0, "5"},
+		}, {
+			name:         "Missing value",
+			measurements: []string{"s1", "s2"},
+			dataTypes:    []TSDataType{INT32, INT32},
+			values:       []interface{}{int32(1)},
+			wantErr:      "2 measurements, 2 dataTypes and 1 values, they should have the same size",
+		}, {
+			name:         "Missing dataType",
+			measurements: []string{"s1", "s2"},
+			dataTypes:    []TSDataType{INT32},
+			values:       []interface{}{int32(1), int32(2)},
+			wantErr:      "2 measurements, 1 dataTypes and 2 values, they should have the same size",
+		}, {
+			name:         "Wrong type",
+			measurements: []string{"s1", "s2", "s3", "s4"},
+			dataTypes:    []TSDataType{INT32, INT32, INT32, INT64},
+			values:       []interface{}{int32(1), int32(2), int32(3), "4"},
+			wantErr:      "value at index 3 is string but dataType is INT64",
+		}, {
+			name:         "Untyped constant",
+			measurements: []string{"s1"},
+			dataTypes:    []TSDataType{INT32},
+			values:       []interface{}{1},
+			wantErr:      "value at index 0 is int but dataType is INT32",
+		}, {
+			name:         "Nil value",
+			measurements: []string{"s1"},
+			dataTypes:    []TSDataType{TEXT},
+			values:       []interface{}{nil},
+			wantErr:      "value at index 0 is nil, dataType is TEXT",
+		}, {
+			name:         "Illegal dataType",
+			measurements: []string{"s1"},
+			dataTypes:    []TSDataType{UNKNOW},
+			values:       []interface{}{int32(1)},
+			wantErr:      "dataType at index 0 is illegal, it must be in (BOOLEAN, INT32, INT64, FLOAT, DOUBLE, TEXT)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRecord(tt.measurements, tt.dataTypes, tt.values)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkRecord() error = %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("checkRecord() error = %v, want %v", err, tt.wantErr)
+			}
+			s := &Session{config: &Config{}}
+			if _, err := s.genTSInsertRecordReq("root.sg.d1", 1, tt.measurements, tt.dataTypes, tt.values); (err != nil) != (tt.wantErr != "") {
+				t.Errorf("genTSInsertRecordReq() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSession_genInsertRecordsReq(t *testing.T) {
+	tests := []struct {
+		name      string
+		dataTypes [][]TSDataType
+		wantErr   error
+	}{
+		{
+			name:      "Valid",
+			dataTypes: [][]TSDataType{{INT32}, {INT32}},
+		}, {
+			name:      "Missing dataTypes",
+			dataTypes: [][]TSDataType{{INT32}},
+			wantErr:   lengthError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Session{config: &Config{}}
+			_, err := s.genInsertRecordsReq([]string{"root.sg.d1", "root.sg.d2"}, [][]string{{"s1"}, {"s1"}}, tt.dataTypes,
+				[][]interface{}{{int32(1)}, {int32(2)}}, []int64{1, 2})
+			if err != tt.wantErr {
+				t.Errorf("genInsertRecordsReq() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSession_toDataSet(t *testing.T) {
+	var queryId int64 = 1
+	message := "syntax error"
+	tests := []struct {
+		name       string
+		resp       *rpc.TSExecuteStatementResp
+		err        error
+		wantErr    bool
+		wantNilSet bool
+	}{
+		{
+			name:    "RPC error",
+			err:     errors.New("connection reset by peer"),
+			wantErr: true,
+		}, {
+			name:    "Failed statement",
+			resp:    &rpc.TSExecuteStatementResp{Status: &rpc.TSStatus{Code: ExecuteStatementError, Message: &message}},
+			wantErr: true,
+		}, {
+			name:       "No result set",
+			resp:       &rpc.TSExecuteStatementResp{Status: &rpc.TSStatus{Code: SuccessStatus}},
+			wantNilSet: true,
+		}, {
+			name: "Result set",
+			resp: &rpc.TSExecuteStatementResp{Status: &rpc.TSStatus{Code: SuccessStatus}, QueryId: &queryId,
+				QueryDataSet: &rpc.TSQueryDataSet{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Session{config: &Config{}}
+			dataSet, err := s.toDataSet("select s1 from root.sg.d1", tt.resp, tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Session.toDataSet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (dataSet == nil) != (tt.wantErr || tt.wantNilSet) {
+				t.Errorf("Session.toDataSet() = %v, want a data set only for a result set", dataSet)
+			}
+		})
+	}
+}
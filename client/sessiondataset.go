@@ -19,14 +19,46 @@
 
 package client
 
-import "github.com/apache/iotdb-client-go/rpc"
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
 
 const (
 	TimestampColumnName = "Time"
+	DeviceColumnName    = "Device"
 )
 
 type SessionDataSet struct {
+	// lastAccess, in unix nanoseconds, clock and registry are set when the
+	// session tracks idle data sets. lastAccess is accessed atomically and kept first
+	// for its 64-bit alignment.
+	lastAccess      int64
 	ioTDBRpcDataSet *IoTDBRpcDataSet
+	registry        *dataSetRegistry
+	registryId      int64
+	clock           Clock
+	allowPartial    bool
+}
+
+// PartialResultError is returned by ReadAll, when partial results are allowed,
+// if reading the result failed after some rows were received.
+type PartialResultError struct {
+	// Rows are the rows read before the failure.
+	Rows []*RowRecord
+	Err  error
+}
+
+func (e *PartialResultError) Error() string {
+	return fmt.Sprintf("partial result of %d rows: %v", len(e.Rows), e.Err)
+}
+
+func (e *PartialResultError) Unwrap() error {
+	return e.Err
 }
 
 // Next prepares the next result row for reading,
@@ -35,6 +67,9 @@ type SessionDataSet struct {
 // consulted Err should be consulted to distinguish between the two cases.
 // This is not goroutine safe
 func (s *SessionDataSet) Next() (bool, error) {
+	if s.registry != nil {
+		atomic.StoreInt64(&s.lastAccess, s.clock.Now().UnixNano())
+	}
 	return s.ioTDBRpcDataSet.next()
 }
 
@@ -72,6 +107,12 @@ func (s *SessionDataSet) GetTimestamp() int64 {
 	return s.ioTDBRpcDataSet.GetTimestamp()
 }
 
+// GetTime returns the timestamp of the row as a time, according to the time
+// precision of the session.
+func (s *SessionDataSet) GetTime() time.Time {
+	return s.ioTDBRpcDataSet.getTime()
+}
+
 func (s *SessionDataSet) GetValue(columnName string) interface{} {
 	return s.ioTDBRpcDataSet.getValue(columnName)
 }
@@ -100,11 +141,115 @@ func (s *SessionDataSet) IsIgnoreTimeStamp() bool {
 	return s.ioTDBRpcDataSet.ignoreTimeStamp
 }
 
+// TotalRowCount returns the total number of rows of the result and true when
+// the count is exact. IoTDB does not report the cardinality of a query up front,
+// so the count is only known once the server has delivered its last batch:
+// either the whole result fitted in the first batch (fewer rows than the fetch
+// size) or iteration has reached the end. Otherwise it returns -1 and false.
+func (s *SessionDataSet) TotalRowCount() (int64, bool) {
+	return s.ioTDBRpcDataSet.getTotalRowCount()
+}
+
+// Stream delivers the rows of the data set to the returned channel from a new
+// goroutine, which fetches the batches from the server as the rows are consumed.
+// The row channel is closed when the result is exhausted, an error happens or
+// ctx is done; the error, ctx.Err() for a cancellation, is then sent on the
+// error channel, which is closed afterwards. The data set is closed when the
+// goroutine stops, which frees the query on the server, and must not be used
+// concurrently by the caller. A panic of the goroutine is recovered and sent as
+// a *PanicError.
+//
+// The goroutine fetches through the connection of the session, which isn't
+// safe for concurrent use: the session is owned by the stream and must not be
+// used until the error channel is closed. A streamed data set is no longer
+// closed by the idle data set reaping of the session.
+func (s *SessionDataSet) Stream(ctx context.Context) (<-chan *RowRecord, <-chan error) {
+	s.untrack()
+	rows := make(chan *RowRecord)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(rows)
+		defer catchPanic(func(err *PanicError) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+		defer s.Close()
+		for {
+			if err := ctx.Err(); err != nil {
+				errs <- err
+				return
+			}
+			hasNext, err := s.Next()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !hasNext {
+				return
+			}
+			record, err := s.GetRowRecord()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case rows <- record:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return rows, errs
+}
+
+// SetCoerceTypes enables the widening conversions of the typed accessors and
+// Scan, see Config.CoerceTypes.
+func (s *SessionDataSet) SetCoerceTypes(coerce bool) {
+	s.ioTDBRpcDataSet.coerceTypes = coerce
+}
+
+// SetAllowPartialResult sets whether ReadAll returns the rows read before a
+// failure, see Config.AllowPartialResults.
+func (s *SessionDataSet) SetAllowPartialResult(allow bool) {
+	s.allowPartial = allow
+}
+
+// ReadAll reads the remaining rows of the data set. When reading fails, for
+// example if the server fails while fetching a batch, the rows read so far are
+// discarded and the error is returned, unless partial results are allowed: the
+// rows are then returned along with a *PartialResultError wrapping the cause, so
+// the caller can decide whether to use them or retry.
+func (s *SessionDataSet) ReadAll() ([]*RowRecord, error) {
+	rows := make([]*RowRecord, 0)
+	for {
+		hasNext, err := s.Next()
+		if err == nil && !hasNext {
+			return rows, nil
+		}
+		var record *RowRecord
+		if err == nil {
+			record, err = s.GetRowRecord()
+		}
+		if err != nil {
+			if s.allowPartial {
+				return rows, &PartialResultError{Rows: rows, Err: err}
+			}
+			return nil, err
+		}
+		rows = append(rows, record)
+	}
+}
+
 func (s *SessionDataSet) IsClosed() bool {
 	return s.ioTDBRpcDataSet.IsClosed()
 }
 
 func (s *SessionDataSet) Close() error {
+	s.untrack()
 	return s.ioTDBRpcDataSet.Close()
 }
 
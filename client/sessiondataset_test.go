@@ -0,0 +1,188 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/iotdb-client-go/rpc"
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func createSessionDataSet() *SessionDataSet {
+	ds := &SessionDataSet{ioTDBRpcDataSet: createIoTDBRpcDataSet()}
+	ds.ioTDBRpcDataSet.emptyResultSet = true
+	return ds
+}
+
+func TestSessionDataSet_Stream(t *testing.T) {
+	tests := []struct {
+		name     string
+		cancel   bool
+		wantRows int
+		wantErr  bool
+	}{
+		{
+			name:     "All rows",
+			cancel:   false,
+			wantRows: 5,
+			wantErr:  false,
+		}, {
+			name:     "Cancelled",
+			cancel:   true,
+			wantRows: 1,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := createSessionDataSet()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			rows, errs := ds.Stream(ctx)
+			count := 0
+			for row := range rows {
+				if row.GetTimestamp() == 0 {
+					t.Errorf("SessionDataSet.Stream() row without timestamp")
+				}
+				count++
+				if tt.cancel {
+					cancel()
+					break
+				}
+			}
+			for range rows {
+			}
+			err := <-errs
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SessionDataSet.Stream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if count != tt.wantRows {
+				t.Errorf("SessionDataSet.Stream() rows = %d, want %d", count, tt.wantRows)
+			}
+			if !ds.IsClosed() {
+				t.Errorf("SessionDataSet.Stream() didn't close the data set")
+			}
+		})
+	}
+}
+
+type failingClient struct {
+	err error
+}
+
+func (c *failingClient) Call(ctx context.Context, method string, args, result thrift.TStruct) error {
+	return c.err
+}
+
+func TestSessionDataSet_ReadAll(t *testing.T) {
+	fetchError := errors.New("connection reset by peer")
+	tests := []struct {
+		name         string
+		allowPartial bool
+		fetchFails   bool
+		wantRows     int
+		wantErr      bool
+	}{
+		{
+			name:     "Complete",
+			wantRows: 5,
+			wantErr:  false,
+		}, {
+			name:         "Failure discards rows",
+			allowPartial: false,
+			fetchFails:   true,
+			wantRows:     0,
+			wantErr:      true,
+		}, {
+			name:         "Failure keeps partial rows",
+			allowPartial: true,
+			fetchFails:   true,
+			wantRows:     5,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := createSessionDataSet()
+			if tt.fetchFails {
+				ds.ioTDBRpcDataSet.emptyResultSet = false
+				ds.ioTDBRpcDataSet.client = rpc.NewTSIServiceClient(&failingClient{err: fetchError})
+			}
+			ds.SetAllowPartialResult(tt.allowPartial)
+			rows, err := ds.ReadAll()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SessionDataSet.ReadAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(rows) != tt.wantRows {
+				t.Errorf("SessionDataSet.ReadAll() rows = %d, want %d", len(rows), tt.wantRows)
+			}
+			if err != nil && !errors.Is(err, fetchError) {
+				t.Errorf("SessionDataSet.ReadAll() error = %v, want it to wrap %v", err, fetchError)
+			}
+			var partial *PartialResultError
+			if errors.As(err, &partial) != tt.allowPartial {
+				t.Errorf("SessionDataSet.ReadAll() error = %v, want a PartialResultError %v", err, tt.allowPartial)
+			}
+		})
+	}
+}
+
+func TestSessionDataSet_WriteCSV(t *testing.T) {
+	row := ";1;1988.2;3333333;12.1;Test Device 1;true\n"
+	header := "Time;root.ln.device1.restart_count;root.ln.device1.price;root.ln.device1.tick_count;root.ln.device1.temperature;root.ln.device1.description;root.ln.device1.status\n"
+	timestamps := []int64{1607596245228, 1607596251620, 1607596255530, 1607596307348, 1607599088383}
+	tests := []struct {
+		name       string
+		options    CSVOptions
+		formatTime func(int64) string
+	}{
+		{
+			name:       "Raw timestamps",
+			options:    CSVOptions{Delimiter: ';'},
+			formatTime: int64ToString,
+		}, {
+			name:    "RFC3339",
+			options: CSVOptions{Delimiter: ';', TimeLayout: time.RFC3339},
+			formatTime: func(timestamp int64) string {
+				return time.Unix(0, timestamp*int64(time.Millisecond)).Format(time.RFC3339)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := header
+			for _, timestamp := range timestamps {
+				want += tt.formatTime(timestamp) + row
+			}
+			buff := &bytes.Buffer{}
+			if err := createSessionDataSet().WriteCSV(buff, tt.options); err != nil {
+				t.Fatal(err)
+			}
+			if got := buff.String(); got != want {
+				t.Errorf("SessionDataSet.WriteCSV() = %v, want %v", got, want)
+			}
+		})
+	}
+}
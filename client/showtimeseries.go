@@ -0,0 +1,183 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TimeseriesInfo describes a time series returned by ShowTimeseries.
+type TimeseriesInfo struct {
+	Path         string
+	Alias        string
+	StorageGroup string
+	// DataType is UNKNOW, and Encoding and Compressor are -1, for the names
+	// the client doesn't know.
+	DataType   TSDataType
+	Encoding   TSEncoding
+	Compressor TSCompressionType
+}
+
+// TimeseriesFilter restricts the time series returned by ShowTimeseries.
+type TimeseriesFilter struct {
+	// DataTypes keeps the time series of these data types, all of them when
+	// it is empty.
+	DataTypes []TSDataType
+}
+
+// The columns of the SHOW TIMESERIES result.
+const (
+	timeseriesColumnName   = "timeseries"
+	aliasColumnName        = "alias"
+	storageGroupColumnName = "storage group"
+	dataTypeColumnName     = "dataType"
+	encodingColumnName     = "encoding"
+	compressionColumnName  = "compression"
+)
+
+var (
+	tsEncodingMap = map[string]TSEncoding{
+		"PLAIN":            PLAIN,
+		"PLAIN_DICTIONARY": PLAIN_DICTIONARY,
+		"RLE":              RLE,
+		"DIFF":             DIFF,
+		"TS_2DIFF":         TS_2DIFF,
+		"BITMAP":           BITMAP,
+		"GORILLA_V1":       GORILLA_V1,
+		"REGULAR":          REGULAR,
+		"GORILLA":          GORILLA,
+	}
+	tsCompressionMap = map[string]TSCompressionType{
+		"UNCOMPRESSED": UNCOMPRESSED,
+		"SNAPPY":       SNAPPY,
+		"GZIP":         GZIP,
+		"LZO":          LZO,
+		"SDT":          SDT,
+		"PAA":          PAA,
+		"PLA":          PLA,
+		"LZ4":          LZ4,
+	}
+)
+
+// ShowTimeseries returns a page of the time series matching a path pattern,
+// such as root.sg.**, with SHOW TIMESERIES: at most limit of them after
+// skipping offset, and whether more pages exist. A limit of 0 returns all the
+// time series after offset.
+//
+// The server can't filter on the data type, so with filter.DataTypes the
+// offset and limit count the matching time series and the client reads the
+// series of the pattern until the page is full.
+func (s *Session) ShowTimeseries(pathPattern string, filter TimeseriesFilter, limit, offset int) ([]TimeseriesInfo, bool, error) {
+	if limit < 0 || offset < 0 {
+		return nil, false, fmt.Errorf("illegal limit %d or offset %d", limit, offset)
+	}
+	serverLimit, serverOffset := 0, 0
+	if len(filter.DataTypes) == 0 {
+		// one more to know whether a next page exists
+		if limit > 0 {
+			serverLimit = limit + 1
+		}
+		serverOffset = offset
+		offset = 0
+	}
+	sql, err := showTimeseriesStatement(pathPattern, serverLimit, serverOffset)
+	if err != nil {
+		return nil, false, err
+	}
+	dataSet, err := s.executeQuery(sql)
+	if err != nil {
+		return nil, false, err
+	}
+	defer dataSet.Close()
+	return readTimeseries(dataSet, filter, limit, offset)
+}
+
+func showTimeseriesStatement(pathPattern string, limit, offset int) (string, error) {
+	if strings.ContainsAny(pathPattern, " ;") {
+		return "", fmt.Errorf("illegal path pattern %s", pathPattern)
+	}
+	sql := "show timeseries"
+	if pathPattern != "" {
+		sql += " " + pathPattern
+	}
+	if limit > 0 {
+		sql += fmt.Sprintf(" limit %d", limit)
+	}
+	if offset > 0 {
+		sql += fmt.Sprintf(" offset %d", offset)
+	}
+	return sql, nil
+}
+
+// readTimeseries reads the time series matching filter, skipping offset of
+// them and stopping after limit, and reports whether one more matched.
+func readTimeseries(dataSet *SessionDataSet, filter TimeseriesFilter, limit, offset int) ([]TimeseriesInfo, bool, error) {
+	timeseries := make([]TimeseriesInfo, 0)
+	for {
+		hasNext, err := dataSet.Next()
+		if err != nil {
+			return nil, false, err
+		}
+		if !hasNext {
+			return timeseries, false, nil
+		}
+		info := TimeseriesInfo{
+			Path:         dataSet.GetText(timeseriesColumnName),
+			Alias:        dataSet.GetText(aliasColumnName),
+			StorageGroup: dataSet.GetText(storageGroupColumnName),
+			DataType:     UNKNOW,
+			Encoding:     -1,
+			Compressor:   -1,
+		}
+		if dataType, ok := tsTypeMap[dataSet.GetText(dataTypeColumnName)]; ok {
+			info.DataType = dataType
+		}
+		if encoding, ok := tsEncodingMap[dataSet.GetText(encodingColumnName)]; ok {
+			info.Encoding = encoding
+		}
+		if compressor, ok := tsCompressionMap[dataSet.GetText(compressionColumnName)]; ok {
+			info.Compressor = compressor
+		}
+		if !filter.matches(info) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if limit > 0 && len(timeseries) == limit {
+			return timeseries, true, nil
+		}
+		timeseries = append(timeseries, info)
+	}
+}
+
+func (f TimeseriesFilter) matches(info TimeseriesInfo) bool {
+	if len(f.DataTypes) == 0 {
+		return true
+	}
+	for _, dataType := range f.DataTypes {
+		if info.DataType == dataType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func Test_showTimeseriesStatement(t *testing.T) {
+	tests := []struct {
+		name        string
+		pathPattern string
+		limit       int
+		offset      int
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "All",
+			want: "show timeseries",
+		}, {
+			name:        "Page",
+			pathPattern: "root.sg.**",
+			limit:       11,
+			offset:      20,
+			want:        "show timeseries root.sg.** limit 11 offset 20",
+		}, {
+			name:        "Injection",
+			pathPattern: "root.sg; delete timeseries root.**",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := showTimeseriesStatement(tt.pathPattern, tt.limit, tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("showTimeseriesStatement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("showTimeseriesStatement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readTimeseries(t *testing.T) {
+	texts := func(values ...string) []byte {
+		buff := make([]byte, 0)
+		for _, s := range values {
+			buff = append(append(buff, int32ToBytes(int32(len(s)))...), s...)
+		}
+		return buff
+	}
+	columns := []string{timeseriesColumnName, aliasColumnName, storageGroupColumnName, dataTypeColumnName, encodingColumnName, compressionColumnName}
+	types := []string{"TEXT", "TEXT", "TEXT", "TEXT", "TEXT", "TEXT"}
+	temperature := TimeseriesInfo{Path: "root.sg.d1.temperature", Alias: "temp", StorageGroup: "root.sg", DataType: FLOAT, Encoding: GORILLA, Compressor: SNAPPY}
+	status := TimeseriesInfo{Path: "root.sg.d1.status", StorageGroup: "root.sg", DataType: BOOLEAN, Encoding: RLE, Compressor: SNAPPY}
+	humidity := TimeseriesInfo{Path: "root.sg.d2.humidity", StorageGroup: "root.sg", DataType: FLOAT, Encoding: -1, Compressor: -1}
+	tests := []struct {
+		name     string
+		filter   TimeseriesFilter
+		limit    int
+		offset   int
+		want     []TimeseriesInfo
+		wantMore bool
+	}{
+		{
+			name: "All",
+			want: []TimeseriesInfo{temperature, status, humidity},
+		}, {
+			name:     "More pages",
+			limit:    2,
+			want:     []TimeseriesInfo{temperature, status},
+			wantMore: true,
+		}, {
+			name:   "Data type",
+			filter: TimeseriesFilter{DataTypes: []TSDataType{FLOAT}},
+			limit:  1,
+			offset: 1,
+			want:   []TimeseriesInfo{humidity},
+		}, {
+			name:     "Data type with more pages",
+			filter:   TimeseriesFilter{DataTypes: []TSDataType{FLOAT}},
+			limit:    1,
+			want:     []TimeseriesInfo{temperature},
+			wantMore: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := rpc.TSQueryDataSet{
+				Time: make([]byte, 24),
+				ValueList: [][]byte{
+					texts("root.sg.d1.temperature", "root.sg.d1.status", "root.sg.d2.humidity"),
+					texts("temp"),
+					texts("root.sg", "root.sg", "root.sg"),
+					texts("FLOAT", "BOOLEAN", "FLOAT"),
+					texts("GORILLA", "RLE", "FREQ"),
+					texts("SNAPPY", "SNAPPY", "ZSTD"),
+				},
+				BitmapList: [][]byte{{224}, {128}, {224}, {224}, {224}, {224}},
+			}
+			ds := NewSessionDataSet("", columns, types, nil, 1, nil, 1, &data, true, DefaultFetchSize)
+			ds.ioTDBRpcDataSet.emptyResultSet = true
+			got, more, err := readTimeseries(ds, tt.filter, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || more != tt.wantMore {
+				t.Errorf("readTimeseries() = %v, %v, want %v, %v", got, more, tt.want, tt.wantMore)
+			}
+		})
+	}
+}
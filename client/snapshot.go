@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+// ErrSnapshotUnsupported is returned by CreateSnapshot when the server predates
+// the schema snapshots.
+var ErrSnapshotUnsupported = errors.New("the server doesn't support schema snapshots, they require 0.12 or later")
+
+// The first server release with schema snapshots.
+const (
+	snapshotMinMajorVersion = 0
+	snapshotMinMinorVersion = 12
+)
+
+const createSnapshotStatement = "create snapshot for schema"
+
+/*
+ *snapshot the schema of the server
+ *return
+ *status of the command
+ *
+ *CREATE SNAPSHOT FOR SCHEMA, available since IoTDB 0.12, writes the schema
+ *snapshot on the server, ErrSnapshotUnsupported is returned for an older
+ *server. The data files aren't part of it, they are backed up by copying the
+ *data directories of the server. No RPC or statement reports the progress of
+ *a backup.
+ */
+func (s *Session) CreateSnapshot() (r *rpc.TSStatus, err error) {
+	if err := s.checkServerVersion(snapshotMinMajorVersion, snapshotMinMinorVersion, "schema snapshots", ErrSnapshotUnsupported); err != nil {
+		return nil, err
+	}
+	return s.ExecuteNonQueryStatement(createSnapshotStatement)
+}
@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// statementClient reports version as the server version and records the
+// statements it executes.
+type statementClient struct {
+	version    string
+	statements []string
+}
+
+func (c *statementClient) Call(ctx context.Context, method string, args, result thrift.TStruct) error {
+	switch r := result.(type) {
+	case *rpc.TSIServiceGetPropertiesResult:
+		r.Success = &rpc.ServerProperties{Version: c.version}
+	case *rpc.TSIServiceExecuteStatementResult:
+		c.statements = append(c.statements, args.(*rpc.TSIServiceExecuteStatementArgs).Req.Statement)
+		r.Success = &rpc.TSExecuteStatementResp{Status: &rpc.TSStatus{Code: SuccessStatus}}
+	}
+	return nil
+}
+
+func TestSession_CreateSnapshot(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        string
+		wantErr        error
+		wantStatements []string
+	}{
+		{
+			name:           "Supported",
+			version:        "0.12.4",
+			wantErr:        nil,
+			wantStatements: []string{createSnapshotStatement},
+		}, {
+			name:           "Newer server",
+			version:        "0.13.1",
+			wantErr:        nil,
+			wantStatements: []string{createSnapshotStatement},
+		}, {
+			name:           "Older server",
+			version:        "0.11.2",
+			wantErr:        ErrSnapshotUnsupported,
+			wantStatements: nil,
+		}, {
+			name:           "Illegal version",
+			version:        "UNKNOWN",
+			wantErr:        nil,
+			wantStatements: []string{createSnapshotStatement},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &statementClient{version: tt.version}
+			s := &Session{config: &Config{Logger: &recordingLogger{}}, client: rpc.NewTSIServiceClient(c)}
+			if _, err := s.CreateSnapshot(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Session.CreateSnapshot() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(c.statements) != len(tt.wantStatements) {
+				t.Fatalf("Session.CreateSnapshot() executed %v, want %v", c.statements, tt.wantStatements)
+			}
+			for i := range c.statements {
+				if c.statements[i] != tt.wantStatements[i] {
+					t.Errorf("Session.CreateSnapshot() executed %v, want %v", c.statements, tt.wantStatements)
+				}
+			}
+		})
+	}
+}
+
+func TestSession_checkServerVersion(t *testing.T) {
+	unsupported := errors.New("unsupported")
+	tests := []struct {
+		name    string
+		client  thrift.TClient
+		wantErr error
+	}{
+		{
+			name:    "Same minor",
+			client:  &propertiesClient{version: "0.12.0"},
+			wantErr: nil,
+		}, {
+			name:    "Newer major",
+			client:  &propertiesClient{version: "1.0.0"},
+			wantErr: nil,
+		}, {
+			name:    "Older minor",
+			client:  &propertiesClient{version: "0.11.9"},
+			wantErr: unsupported,
+		}, {
+			name:    "Unknown version",
+			client:  &failingClient{err: errors.New("connection reset by peer")},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Session{config: &Config{Logger: &recordingLogger{}}, client: rpc.NewTSIServiceClient(tt.client)}
+			if err := s.checkServerVersion(0, 12, "a feature", unsupported); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Session.checkServerVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"net"
+)
+
+// The bounds of Config.SendBufferSize and Config.RecvBufferSize.
+const (
+	MinSocketBufferSize = 4 << 10
+	MaxSocketBufferSize = 64 << 20
+)
+
+func checkSocketBufferSize(size int) error {
+	if size != 0 && (size < MinSocketBufferSize || size > MaxSocketBufferSize) {
+		return fmt.Errorf("illegal socket buffer size %d, it must be between %d and %d bytes", size, MinSocketBufferSize, MaxSocketBufferSize)
+	}
+	return nil
+}
+
+// setSocketBufferSizes sets the buffer sizes of a TCP connection, the sizes
+// which are 0 are left unchanged.
+func setSocketBufferSizes(conn net.Conn, sendBufferSize, recvBufferSize int) error {
+	if sendBufferSize == 0 && recvBufferSize == 0 {
+		return nil
+	}
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return fmt.Errorf("can't set the buffer sizes of a %T connection", conn)
+	}
+	if sendBufferSize != 0 {
+		if err := tcpConn.SetWriteBuffer(sendBufferSize); err != nil {
+			return fmt.Errorf("set the send buffer size: %w", err)
+		}
+	}
+	if recvBufferSize != 0 {
+		if err := tcpConn.SetReadBuffer(recvBufferSize); err != nil {
+			return fmt.Errorf("set the receive buffer size: %w", err)
+		}
+	}
+	return nil
+}
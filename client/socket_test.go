@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"net"
+	"testing"
+)
+
+func Test_checkSocketBufferSize(t *testing.T) {
+	tests := []struct {
+		size    int
+		wantErr bool
+	}{
+		{size: 0, wantErr: false},
+		{size: MinSocketBufferSize, wantErr: false},
+		{size: MaxSocketBufferSize, wantErr: false},
+		{size: MinSocketBufferSize - 1, wantErr: true},
+		{size: MaxSocketBufferSize + 1, wantErr: true},
+		{size: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := checkSocketBufferSize(tt.size); (err != nil) != tt.wantErr {
+			t.Errorf("checkSocketBufferSize(%d) error = %v, wantErr %v", tt.size, err, tt.wantErr)
+		}
+	}
+}
+
+func Test_setSocketBufferSizes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("can't listen on the loopback interface: %v", err)
+	}
+	defer listener.Close()
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := setSocketBufferSizes(conn, 1<<20, 1<<20); err != nil {
+		t.Errorf("setSocketBufferSizes() error = %v", err)
+	}
+
+	pipe, other := net.Pipe()
+	defer pipe.Close()
+	defer other.Close()
+	if err := setSocketBufferSizes(pipe, 0, 0); err != nil {
+		t.Errorf("setSocketBufferSizes() without sizes error = %v", err)
+	}
+	if err := setSocketBufferSizes(pipe, 1<<20, 0); err == nil {
+		t.Error("setSocketBufferSizes() accepted a pipe")
+	}
+}
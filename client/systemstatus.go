@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+// SystemStatus is the read/write status of the server.
+type SystemStatus int
+
+const (
+	// SystemStatusUnknown means the status hasn't been set through the session.
+	SystemStatusUnknown SystemStatus = iota
+	// SystemStatusRunning means the server accepts writes.
+	SystemStatusRunning
+	// SystemStatusReadOnly means the server rejects writes.
+	SystemStatusReadOnly
+)
+
+var (
+	// ErrSystemReadOnly is returned by inserts when the server is read-only, and
+	// wrapped by VerifySuccess for a ReadOnlySystemError status.
+	ErrSystemReadOnly = errors.New("the system is read-only")
+
+	errSystemStatusUnknown = errors.New("the system status is unknown, the server doesn't report it and it wasn't set through this session")
+)
+
+/*
+ *set the read/write status of the server
+ *params
+ *status: SystemStatus, SystemStatusRunning or SystemStatusReadOnly
+ *return
+ *error: correctness of operation
+ *
+ *Once the server has been set read-only by the session, its inserts fail with
+ *ErrSystemReadOnly without reaching the server.
+ */
+func (s *Session) SetSystemStatus(status SystemStatus) (r *rpc.TSStatus, err error) {
+	var sql string
+	switch status {
+	case SystemStatusRunning:
+		sql = "set system to writable"
+	case SystemStatusReadOnly:
+		sql = "set system to readonly"
+	default:
+		return nil, errors.New("status must be SystemStatusRunning or SystemStatusReadOnly")
+	}
+	r, err = s.ExecuteNonQueryStatement(sql)
+	if err == nil && VerifySuccess(r) == nil {
+		s.systemStatus = status
+	}
+	return r, err
+}
+
+// GetSystemStatus returns the status of the server as last set through the
+// session. The server has no RPC or statement reporting it, so an error is
+// returned when the session never set it.
+func (s *Session) GetSystemStatus() (SystemStatus, error) {
+	if s.systemStatus == SystemStatusUnknown {
+		return SystemStatusUnknown, errSystemStatusUnknown
+	}
+	return s.systemStatus, nil
+}
+
+func (s *Session) checkWritable() error {
+	if s.systemStatus == SystemStatusReadOnly {
+		return ErrSystemReadOnly
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func TestSession_GetSystemStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		systemStatus SystemStatus
+		want         SystemStatus
+		wantErr      bool
+		wantWritable bool
+	}{
+		{
+			name:         "Unknown",
+			systemStatus: SystemStatusUnknown,
+			want:         SystemStatusUnknown,
+			wantErr:      true,
+			wantWritable: true,
+		}, {
+			name:         "Running",
+			systemStatus: SystemStatusRunning,
+			want:         SystemStatusRunning,
+			wantErr:      false,
+			wantWritable: true,
+		}, {
+			name:         "ReadOnly",
+			systemStatus: SystemStatusReadOnly,
+			want:         SystemStatusReadOnly,
+			wantErr:      false,
+			wantWritable: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Session{config: &Config{}, systemStatus: tt.systemStatus}
+			got, err := s.GetSystemStatus()
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Session.GetSystemStatus() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+			if err := s.checkWritable(); tt.wantWritable == errors.Is(err, ErrSystemReadOnly) {
+				t.Errorf("Session.checkWritable() error = %v, wantWritable %v", err, tt.wantWritable)
+			}
+		})
+	}
+}
+
+func TestVerifySuccess_readOnly(t *testing.T) {
+	message := "Database is read-only, and does not accept non-query operation now"
+	err := VerifySuccess(&rpc.TSStatus{Code: ReadOnlySystemError, Message: &message})
+	if !errors.Is(err, ErrSystemReadOnly) {
+		t.Errorf("VerifySuccess() error = %v, want ErrSystemReadOnly", err)
+	}
+}
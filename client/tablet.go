@@ -26,14 +26,26 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
+	"text/tabwriter"
 )
 
+// DefaultTabletStringRows is the number of rows Tablet.String prints.
+const DefaultTabletStringRows = 10
+
 type MeasurementSchema struct {
 	Measurement string
 	DataType    TSDataType
 	Encoding    TSEncoding
 	Compressor  TSCompressionType
 	Properties  map[string]string
+	// Alias is an optional alias of the measurement. When it is set, inserts
+	// reference the timeseries by its alias and the server resolves it to the
+	// canonical measurement, which must have been created with this alias.
+	Alias string
+	// Required makes Validate, and so InsertTablet, fail when a cell of the
+	// column was never set, instead of inserting its zero value.
+	Required bool
 }
 
 type Tablet struct {
@@ -42,10 +54,42 @@ type Tablet struct {
 	timestamps         []int64
 	values             []interface{}
 	rowCount           int
+	sorted             bool
+	// bitMaps marks, per column, the cells which have been set.
+	bitMaps []*bitMap
+	// validators are run over every row by Validate.
+	validators       []RowValidator
+	validationPolicy ValidationPolicy
 }
 
+// SetTimestamp sets the timestamp of a row. Timestamps are signed, zero and
+// negative values (before the epoch) are valid.
 func (t *Tablet) SetTimestamp(timestamp int64, rowIndex int) {
 	t.timestamps[rowIndex] = timestamp
+	t.sorted = false
+}
+
+// TimeRange returns the smallest and largest timestamps of the tablet, ok is
+// false when the tablet has no rows. It reads the endpoints when the tablet is
+// known to be sorted, that is Sort was called after the last SetTimestamp, and
+// scans the timestamps otherwise.
+func (t *Tablet) TimeRange() (min, max int64, ok bool) {
+	if t.rowCount == 0 {
+		return 0, 0, false
+	}
+	if t.sorted {
+		return t.timestamps[0], t.timestamps[t.rowCount-1], true
+	}
+	min, max = t.timestamps[0], t.timestamps[0]
+	for _, timestamp := range t.timestamps[1:t.rowCount] {
+		if timestamp < min {
+			min = timestamp
+		}
+		if timestamp > max {
+			max = timestamp
+		}
+	}
+	return min, max, true
 }
 
 func (t *Tablet) SetValueAt(value interface{}, columnIndex, rowIndex int) error {
@@ -123,6 +167,130 @@ func (t *Tablet) SetValueAt(value interface{}, columnIndex, rowIndex int) error
 			return fmt.Errorf("Illegal argument value %v %v", value, reflect.TypeOf(value))
 		}
 	}
+	if t.bitMaps != nil {
+		t.bitMaps[columnIndex].mark(rowIndex)
+	}
+	return nil
+}
+
+// isSet reports whether the cell has been set, cells of a tablet which doesn't
+// track them are considered set.
+func (t *Tablet) isSet(columnIndex, rowIndex int) bool {
+	return t.bitMaps == nil || t.bitMaps[columnIndex].isMarked(rowIndex)
+}
+
+// maxReportedRows bounds the rows listed in a Validate error.
+const maxReportedRows = 10
+
+// Validate checks the tablet before it is inserted: every cell of a Required
+// column must have been set.
+func (t *Tablet) Validate() error {
+	for columnIndex, schema := range t.measurementSchemas {
+		if !schema.Required {
+			continue
+		}
+		unset := make([]int, 0)
+		count := 0
+		for rowIndex := 0; rowIndex < t.rowCount; rowIndex++ {
+			if !t.isSet(columnIndex, rowIndex) {
+				if count < maxReportedRows {
+					unset = append(unset, rowIndex)
+				}
+				count++
+			}
+		}
+		if count > 0 {
+			return fmt.Errorf("required column %s has %d unset cells, at rows %v", schema.Measurement, count, unset)
+		}
+	}
+	return t.runValidators()
+}
+
+// RowValidator checks a row of a tablet, values holds the value of every
+// column, nil for the unset cells. values is reused between the rows and must
+// not be retained.
+type RowValidator func(rowIndex int, ts int64, values []interface{}) error
+
+// ValidationPolicy tells Validate how to handle the rows rejected by the
+// validators.
+type ValidationPolicy int
+
+const (
+	// ValidationFailFast stops at the first rejected row.
+	ValidationFailFast ValidationPolicy = iota
+	// ValidationCollectAll runs the validators over every row and reports all
+	// the rejected ones.
+	ValidationCollectAll
+)
+
+// RowViolation is a row rejected by a RowValidator.
+type RowViolation struct {
+	RowIndex  int
+	Timestamp int64
+	Err       error
+}
+
+// ValidationError is returned by Validate when validators rejected rows.
+type ValidationError struct {
+	Violations []RowViolation
+}
+
+func (e *ValidationError) Error() string {
+	buff := bytes.Buffer{}
+	fmt.Fprintf(&buff, "%d rows rejected:", len(e.Violations))
+	for i, violation := range e.Violations {
+		if i == maxReportedRows {
+			buff.WriteString(" ...")
+			break
+		}
+		fmt.Fprintf(&buff, " row %d: %v;", violation.RowIndex, violation.Err)
+	}
+	return buff.String()
+}
+
+// AddValidator registers a validator run over every row by Validate, and so by
+// InsertTablet, after the Required columns have been checked.
+func (t *Tablet) AddValidator(validator RowValidator) {
+	t.validators = append(t.validators, validator)
+}
+
+// SetValidationPolicy sets how Validate handles the rows rejected by the
+// validators, ValidationFailFast by default.
+func (t *Tablet) SetValidationPolicy(policy ValidationPolicy) {
+	t.validationPolicy = policy
+}
+
+func (t *Tablet) runValidators() error {
+	if len(t.validators) == 0 {
+		return nil
+	}
+	var violations []RowViolation
+	values := make([]interface{}, len(t.measurementSchemas))
+	for rowIndex := 0; rowIndex < t.rowCount; rowIndex++ {
+		for columnIndex := range t.measurementSchemas {
+			values[columnIndex] = nil
+			if t.isSet(columnIndex, rowIndex) {
+				value, err := t.GetValueAt(columnIndex, rowIndex)
+				if err != nil {
+					return err
+				}
+				values[columnIndex] = value
+			}
+		}
+		timestamp := t.timestamps[rowIndex]
+		for _, validator := range t.validators {
+			if err := validator(rowIndex, timestamp, values); err != nil {
+				violations = append(violations, RowViolation{RowIndex: rowIndex, Timestamp: timestamp, Err: err})
+				if t.validationPolicy == ValidationFailFast {
+					return &ValidationError{Violations: violations}
+				}
+				break
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
 	return nil
 }
 
@@ -174,6 +342,74 @@ func (t *Tablet) GetMeasurements() []string {
 	return measurements
 }
 
+// getInsertMeasurements returns the measurement names sent by inserts, which is
+// the alias of a measurement when it has one.
+func (t *Tablet) getInsertMeasurements() []string {
+	measurements := make([]string, len(t.measurementSchemas))
+	for i, s := range t.measurementSchemas {
+		if s.Alias != "" {
+			measurements[i] = s.Alias
+		} else {
+			measurements[i] = s.Measurement
+		}
+	}
+	return measurements
+}
+
+// ColumnOrder returns the indexes of the measurements in the tablet, in the
+// order given, for InsertTabletWithColumnOrder.
+func (t *Tablet) ColumnOrder(measurements ...string) ([]int, error) {
+	order := make([]int, len(measurements))
+	for i, measurement := range measurements {
+		order[i] = -1
+		for columnIndex, schema := range t.measurementSchemas {
+			if schema.Measurement == measurement {
+				order[i] = columnIndex
+				break
+			}
+		}
+		if order[i] < 0 {
+			return nil, fmt.Errorf("measurement %s is not in the tablet", measurement)
+		}
+	}
+	return order, nil
+}
+
+// reordered returns a tablet sharing the rows of t with its columns in order,
+// a permutation of the column indexes. t is left unchanged.
+func (t *Tablet) reordered(order []int) (*Tablet, error) {
+	if len(order) != len(t.measurementSchemas) {
+		return nil, fmt.Errorf("column order has %d columns, the tablet %d", len(order), len(t.measurementSchemas))
+	}
+	seen := make([]bool, len(order))
+	schemas := make([]*MeasurementSchema, len(order))
+	values := make([]interface{}, len(order))
+	var bitMaps []*bitMap
+	if t.bitMaps != nil {
+		bitMaps = make([]*bitMap, len(order))
+	}
+	for i, columnIndex := range order {
+		if columnIndex < 0 || columnIndex >= len(order) || seen[columnIndex] {
+			return nil, fmt.Errorf("column order %v is not a permutation of the columns", order)
+		}
+		seen[columnIndex] = true
+		schemas[i] = t.measurementSchemas[columnIndex]
+		values[i] = t.values[columnIndex]
+		if bitMaps != nil {
+			bitMaps[i] = t.bitMaps[columnIndex]
+		}
+	}
+	return &Tablet{
+		deviceId:           t.deviceId,
+		measurementSchemas: schemas,
+		timestamps:         t.timestamps,
+		values:             values,
+		rowCount:           t.rowCount,
+		sorted:             t.sorted,
+		bitMaps:            bitMaps,
+	}, nil
+}
+
 func (t *Tablet) getDataTypes() []int32 {
 	types := make([]int32, len(t.measurementSchemas))
 	for i, s := range t.measurementSchemas {
@@ -208,57 +444,440 @@ func (t *Tablet) getValuesBytes() ([]byte, error) {
 	return buff.Bytes(), nil
 }
 
+// Sort sorts the rows of the tablet by timestamp in ascending order, rows with
+// equal timestamps keep their relative order.
 func (t *Tablet) Sort() error {
-	sortFunc := func(i int, j int) bool {
-		return t.timestamps[i] < t.timestamps[j]
-	}
+	index := sortedIndex(t.timestamps[:t.rowCount])
 	for i, schema := range t.measurementSchemas {
 		switch schema.DataType {
 		case BOOLEAN:
-			sort.Slice(t.values[i].([]bool), sortFunc)
+			values := t.values[i].([]bool)
+			sorted := make([]bool, len(values))
+			for row, from := range index {
+				sorted[row] = values[from]
+			}
+			t.values[i] = sorted
 		case INT32:
-			sort.Slice(t.values[i].([]int32), sortFunc)
+			values := t.values[i].([]int32)
+			sorted := make([]int32, len(values))
+			for row, from := range index {
+				sorted[row] = values[from]
+			}
+			t.values[i] = sorted
 		case INT64:
-			sort.Slice(t.values[i].([]int64), sortFunc)
+			values := t.values[i].([]int64)
+			sorted := make([]int64, len(values))
+			for row, from := range index {
+				sorted[row] = values[from]
+			}
+			t.values[i] = sorted
 		case FLOAT:
-			sort.Slice(t.values[i].([]float32), sortFunc)
+			values := t.values[i].([]float32)
+			sorted := make([]float32, len(values))
+			for row, from := range index {
+				sorted[row] = values[from]
+			}
+			t.values[i] = sorted
 		case DOUBLE:
-			sort.Slice(t.values[i].([]float64), sortFunc)
+			values := t.values[i].([]float64)
+			sorted := make([]float64, len(values))
+			for row, from := range index {
+				sorted[row] = values[from]
+			}
+			t.values[i] = sorted
 		case TEXT:
-			sort.Slice(t.values[i].([]string), sortFunc)
+			values := t.values[i].([]string)
+			sorted := make([]string, len(values))
+			for row, from := range index {
+				sorted[row] = values[from]
+			}
+			t.values[i] = sorted
 		default:
 			return fmt.Errorf("Illegal datatype %v", schema.DataType)
 		}
 	}
-	sort.Slice(t.timestamps, sortFunc)
+	timestamps := make([]int64, len(t.timestamps))
+	for row, from := range index {
+		timestamps[row] = t.timestamps[from]
+	}
+	t.timestamps = timestamps
+	for i, b := range t.bitMaps {
+		t.bitMaps[i] = b.permute(index)
+	}
+	t.sorted = true
 	return nil
 }
 
-func NewTablet(deviceId string, measurementSchemas []*MeasurementSchema, rowCount int) (*Tablet, error) {
-	tablet := &Tablet{
-		deviceId:           deviceId,
-		measurementSchemas: measurementSchemas,
-		rowCount:           rowCount,
+// sortedIndex returns the row indexes ordered by ascending timestamp. The
+// comparison is signed, negative timestamps sort before zero.
+func sortedIndex(timestamps []int64) []int {
+	index := make([]int, len(timestamps))
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(i, j int) bool {
+		return timestamps[index[i]] < timestamps[index[j]]
+	})
+	return index
+}
+
+// Append appends the rows of other to the tablet. Both tablets must have the
+// same device id and measurement schemas, the rows of other are copied.
+func (t *Tablet) Append(other *Tablet) error {
+	if other.deviceId != t.deviceId {
+		return fmt.Errorf("can't append a tablet of device %s to a tablet of device %s", other.deviceId, t.deviceId)
 	}
-	tablet.timestamps = make([]int64, rowCount)
-	tablet.values = make([]interface{}, len(measurementSchemas))
-	for i, schema := range tablet.measurementSchemas {
+	if !sameColumns(t, other) {
+		return errors.New("can't append a tablet with different measurement schemas")
+	}
+	for i, schema := range t.measurementSchemas {
 		switch schema.DataType {
 		case BOOLEAN:
-			tablet.values[i] = make([]bool, rowCount)
+			t.values[i] = append(t.values[i].([]bool)[:t.rowCount], other.values[i].([]bool)[:other.rowCount]...)
 		case INT32:
-			tablet.values[i] = make([]int32, rowCount)
+			t.values[i] = append(t.values[i].([]int32)[:t.rowCount], other.values[i].([]int32)[:other.rowCount]...)
 		case INT64:
-			tablet.values[i] = make([]int64, rowCount)
+			t.values[i] = append(t.values[i].([]int64)[:t.rowCount], other.values[i].([]int64)[:other.rowCount]...)
 		case FLOAT:
-			tablet.values[i] = make([]float32, rowCount)
+			t.values[i] = append(t.values[i].([]float32)[:t.rowCount], other.values[i].([]float32)[:other.rowCount]...)
 		case DOUBLE:
-			tablet.values[i] = make([]float64, rowCount)
+			t.values[i] = append(t.values[i].([]float64)[:t.rowCount], other.values[i].([]float64)[:other.rowCount]...)
 		case TEXT:
-			tablet.values[i] = make([]string, rowCount)
+			t.values[i] = append(t.values[i].([]string)[:t.rowCount], other.values[i].([]string)[:other.rowCount]...)
 		default:
-			return nil, fmt.Errorf("Illegal datatype %v", schema.DataType)
+			return fmt.Errorf("Illegal datatype %v", schema.DataType)
+		}
+	}
+	if t.bitMaps != nil {
+		for i, b := range t.bitMaps {
+			appended := newBitMap(t.rowCount + other.rowCount)
+			for row := 0; row < t.rowCount; row++ {
+				if b.isMarked(row) {
+					appended.mark(row)
+				}
+			}
+			for row := 0; row < other.rowCount; row++ {
+				if other.isSet(i, row) {
+					appended.mark(t.rowCount + row)
+				}
+			}
+			t.bitMaps[i] = appended
+		}
+	}
+	t.timestamps = append(t.timestamps[:t.rowCount], other.timestamps[:other.rowCount]...)
+	t.rowCount += other.rowCount
+	t.sorted = false
+	return nil
+}
+
+// AppendRows appends a block of rows to the tablet, columns holds one slice
+// per measurement schema, []bool, []int32, []int64, []float32, []float64 or
+// []string by DataType, of len(timestamps) values. The types and the lengths
+// are checked before anything is appended, the values are copied.
+func (t *Tablet) AppendRows(timestamps []int64, columns []interface{}) error {
+	if len(columns) != len(t.measurementSchemas) {
+		return fmt.Errorf("%d columns for %d measurements", len(columns), len(t.measurementSchemas))
+	}
+	for i, schema := range t.measurementSchemas {
+		columnValues, err := newColumnValues(schema.DataType, 0)
+		if err != nil {
+			return err
+		}
+		if reflect.TypeOf(columns[i]) != reflect.TypeOf(columnValues) {
+			return fmt.Errorf("values of measurement %s must be %T, got %T", schema.Measurement, columnValues, columns[i])
+		}
+		if length := reflect.ValueOf(columns[i]).Len(); length != len(timestamps) {
+			return fmt.Errorf("measurement %s has %d values for %d rows", schema.Measurement, length, len(timestamps))
+		}
+	}
+	return t.Append(&Tablet{
+		deviceId:           t.deviceId,
+		measurementSchemas: t.measurementSchemas,
+		timestamps:         timestamps,
+		values:             columns,
+		rowCount:           len(timestamps),
+	})
+}
+
+// MergeColumns returns a new tablet with the columns of the tablet followed by
+// the columns of other, for the same rows. Both tablets must have the same
+// device id and identical timestamps, and no measurement in common. Unlike
+// Append, which adds rows, it adds columns.
+func (t *Tablet) MergeColumns(other *Tablet) (*Tablet, error) {
+	if other.deviceId != t.deviceId {
+		return nil, fmt.Errorf("can't merge a tablet of device %s into a tablet of device %s", other.deviceId, t.deviceId)
+	}
+	if other.rowCount != t.rowCount {
+		return nil, fmt.Errorf("can't merge a tablet of %d rows into a tablet of %d rows", other.rowCount, t.rowCount)
+	}
+	for row := 0; row < t.rowCount; row++ {
+		if t.timestamps[row] != other.timestamps[row] {
+			return nil, fmt.Errorf("timestamps differ at row %d: %d and %d", row, t.timestamps[row], other.timestamps[row])
+		}
+	}
+	measurements := make(map[string]bool)
+	for _, schema := range t.measurementSchemas {
+		measurements[schema.Measurement] = true
+	}
+	for _, schema := range other.measurementSchemas {
+		if measurements[schema.Measurement] {
+			return nil, fmt.Errorf("duplicate measurement %s", schema.Measurement)
+		}
+	}
+
+	schemas := make([]*MeasurementSchema, 0, len(t.measurementSchemas)+len(other.measurementSchemas))
+	schemas = append(append(schemas, t.measurementSchemas...), other.measurementSchemas...)
+	merged, err := NewTablet(t.deviceId, schemas, t.rowCount)
+	if err != nil {
+		return nil, err
+	}
+	copy(merged.timestamps, t.timestamps)
+	columnIndex := 0
+	for _, source := range []*Tablet{t, other} {
+		for i := range source.measurementSchemas {
+			for row := 0; row < source.rowCount; row++ {
+				if !source.isSet(i, row) {
+					continue
+				}
+				value, err := source.GetValueAt(i, row)
+				if err != nil {
+					return nil, err
+				}
+				if err = merged.SetValueAt(value, columnIndex, row); err != nil {
+					return nil, err
+				}
+			}
+			columnIndex++
+		}
+	}
+	merged.sorted = t.sorted
+	return merged, nil
+}
+
+// AppendColumn adds a column after the existing ones, for example a measurement
+// derived from the others. values must be a slice of the Go type of the data
+// type, []float64 for DOUBLE, with one value per row, and every cell of the
+// column is set. The values are copied.
+func (t *Tablet) AppendColumn(schema *MeasurementSchema, values interface{}) error {
+	for _, s := range t.measurementSchemas {
+		if s.Measurement == schema.Measurement {
+			return fmt.Errorf("duplicate measurement %s", schema.Measurement)
+		}
+	}
+	columnValues, err := newColumnValues(schema.DataType, 0)
+	if err != nil {
+		return err
+	}
+	if reflect.TypeOf(values) != reflect.TypeOf(columnValues) {
+		return fmt.Errorf("values of measurement %s must be %T, got %T", schema.Measurement, columnValues, values)
+	}
+	if length := reflect.ValueOf(values).Len(); length != t.rowCount {
+		return fmt.Errorf("measurement %s has %d values for %d rows", schema.Measurement, length, t.rowCount)
+	}
+
+	// Copy the schemas, NewTablet may share the slice of the caller.
+	schemas := make([]*MeasurementSchema, len(t.measurementSchemas), len(t.measurementSchemas)+1)
+	copy(schemas, t.measurementSchemas)
+	columnValues, _ = newColumnValues(schema.DataType, t.rowCount)
+	reflect.Copy(reflect.ValueOf(columnValues), reflect.ValueOf(values))
+	t.measurementSchemas = append(schemas, schema)
+	t.values = append(t.values, columnValues)
+	if t.bitMaps != nil {
+		bitMap := newBitMap(t.rowCount)
+		for row := 0; row < t.rowCount; row++ {
+			bitMap.mark(row)
+		}
+		t.bitMaps = append(t.bitMaps, bitMap)
+	}
+	return nil
+}
+
+// MapColumn replaces in place every set cell of a column with fn applied to
+// it. fn must be a func(T) T where T is the Go type of the column, for example
+// func(float64) float64 for a DOUBLE column or func(string) string for TEXT.
+func (t *Tablet) MapColumn(columnIndex int, fn interface{}) error {
+	if columnIndex < 0 || columnIndex >= len(t.measurementSchemas) {
+		return fmt.Errorf("Illegal argument columnIndex %d", columnIndex)
+	}
+	values := reflect.ValueOf(t.values[columnIndex])
+	elemType := values.Type().Elem()
+	fnValue := reflect.ValueOf(fn)
+	fnType := fnValue.Type()
+	if fnValue.Kind() != reflect.Func || fnType.NumIn() != 1 || fnType.NumOut() != 1 ||
+		fnType.In(0) != elemType || fnType.Out(0) != elemType {
+		return fmt.Errorf("fn must be func(%v) %v for column %s of type %s, got %v", elemType, elemType,
+			t.measurementSchemas[columnIndex].Measurement, dataTypeName(t.measurementSchemas[columnIndex].DataType), fnType)
+	}
+	args := make([]reflect.Value, 1)
+	for row := 0; row < t.rowCount; row++ {
+		if !t.isSet(columnIndex, row) {
+			continue
+		}
+		args[0] = values.Index(row)
+		values.Index(row).Set(fnValue.Call(args)[0])
+	}
+	return nil
+}
+
+// sameColumns reports whether both tablets have the same measurements with the
+// same data types, in the same order.
+func sameColumns(t, other *Tablet) bool {
+	if len(t.measurementSchemas) != len(other.measurementSchemas) {
+		return false
+	}
+	for i, schema := range t.measurementSchemas {
+		otherSchema := other.measurementSchemas[i]
+		if schema.Measurement != otherSchema.Measurement || schema.DataType != otherSchema.DataType {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the tablet as an aligned table, see Format.
+func (t *Tablet) String() string {
+	return t.Format(DefaultTabletStringRows)
+}
+
+// Format returns the device id, a header of the measurement names and types,
+// and up to maxRows rows of values as an aligned table. The remaining rows are
+// summarized in a trailing line, maxRows < 0 prints all of them. A cell which
+// was never set is printed as <unset:0>, with the zero value of its type which
+// an insert sends.
+func (t *Tablet) Format(maxRows int) string {
+	buff := &strings.Builder{}
+	fmt.Fprintf(buff, "Tablet %s, %d rows\n", t.deviceId, t.rowCount)
+
+	w := tabwriter.NewWriter(buff, 0, 0, 2, ' ', 0)
+	header := make([]string, 0, len(t.measurementSchemas)+1)
+	header = append(header, TimestampColumnName)
+	for _, schema := range t.measurementSchemas {
+		header = append(header, fmt.Sprintf("%s(%s)", schema.Measurement, dataTypeName(schema.DataType)))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	rows := t.rowCount
+	if maxRows >= 0 && rows > maxRows {
+		rows = maxRows
+	}
+	for rowIndex := 0; rowIndex < rows; rowIndex++ {
+		cells := make([]string, 0, len(header))
+		cells = append(cells, int64ToString(t.timestamps[rowIndex]))
+		for columnIndex := range t.measurementSchemas {
+			value, err := t.GetValueAt(columnIndex, rowIndex)
+			cell := "<invalid>"
+			if err == nil {
+				if s, ok := value.(string); ok {
+					cell = fmt.Sprintf("%q", s)
+				} else {
+					cell = fmt.Sprint(value)
+				}
+			}
+			if !t.isSet(columnIndex, rowIndex) {
+				cell = "<unset:" + cell + ">"
+			}
+			cells = append(cells, cell)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	w.Flush()
+	if rows < t.rowCount {
+		fmt.Fprintf(buff, "... %d more rows\n", t.rowCount-rows)
+	}
+	return buff.String()
+}
+
+func dataTypeName(dataType TSDataType) string {
+	for name, t := range tsTypeMap {
+		if t == dataType {
+			return name
+		}
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", dataType)
+}
+
+// TabletOptions tunes how NewTabletWithOptions builds a tablet.
+type TabletOptions struct {
+	// SkipInvalidColumns drops the columns with an illegal DataType instead of
+	// failing the whole tablet.
+	SkipInvalidColumns bool
+	// ValidateNames checks the device id and the measurements with
+	// ValidatePath and ValidateIdentifier.
+	ValidateNames bool
+}
+
+// SkippedColumn describes a column dropped by NewTabletWithOptions.
+type SkippedColumn struct {
+	// Index is the position of the column in the measurementSchemas passed in.
+	Index  int
+	Schema *MeasurementSchema
+	Reason error
+}
+
+func newColumnValues(dataType TSDataType, rowCount int) (interface{}, error) {
+	switch dataType {
+	case BOOLEAN:
+		return make([]bool, rowCount), nil
+	case INT32:
+		return make([]int32, rowCount), nil
+	case INT64:
+		return make([]int64, rowCount), nil
+	case FLOAT:
+		return make([]float32, rowCount), nil
+	case DOUBLE:
+		return make([]float64, rowCount), nil
+	case TEXT:
+		return make([]string, rowCount), nil
+	default:
+		return nil, fmt.Errorf("Illegal datatype %v", dataType)
+	}
+}
+
+func NewTablet(deviceId string, measurementSchemas []*MeasurementSchema, rowCount int) (*Tablet, error) {
+	tablet, _, err := NewTabletWithOptions(deviceId, measurementSchemas, rowCount, TabletOptions{})
+	return tablet, err
+}
+
+// NewTabletWithOptions is NewTablet with options. With SkipInvalidColumns the
+// columns having an illegal DataType are left out of the tablet and reported
+// in the returned slice, otherwise the first one fails the construction.
+func NewTabletWithOptions(deviceId string, measurementSchemas []*MeasurementSchema, rowCount int, options TabletOptions) (*Tablet, []SkippedColumn, error) {
+	if options.ValidateNames {
+		measurements := make([]string, len(measurementSchemas))
+		for i, schema := range measurementSchemas {
+			measurements[i] = schema.Measurement
+		}
+		if err := validateNames(deviceId, measurements); err != nil {
+			return nil, nil, err
+		}
+	}
+	var skipped []SkippedColumn
+	schemas := make([]*MeasurementSchema, 0, len(measurementSchemas))
+	values := make([]interface{}, 0, len(measurementSchemas))
+	bitMaps := make([]*bitMap, 0, len(measurementSchemas))
+	for i, schema := range measurementSchemas {
+		columnValues, err := newColumnValues(schema.DataType, rowCount)
+		if err != nil {
+			if !options.SkipInvalidColumns {
+				return nil, nil, err
+			}
+			skipped = append(skipped, SkippedColumn{Index: i, Schema: schema, Reason: err})
+			continue
 		}
+		schemas = append(schemas, schema)
+		values = append(values, columnValues)
+		bitMaps = append(bitMaps, newBitMap(rowCount))
+	}
+	if len(skipped) == 0 {
+		schemas = measurementSchemas
+	}
+	tablet := &Tablet{
+		deviceId:           deviceId,
+		measurementSchemas: schemas,
+		timestamps:         make([]int64, rowCount),
+		values:             values,
+		rowCount:           rowCount,
+		bitMaps:            bitMaps,
 	}
-	return tablet, nil
+	return tablet, skipped, nil
 }
@@ -20,6 +20,8 @@
 package client
 
 import (
+	"fmt"
+	"math"
 	"reflect"
 	"testing"
 )
@@ -441,3 +443,881 @@ func TestTablet_Sort(t *testing.T) {
 		})
 	}
 }
+
+func TestTablet_Format(t *testing.T) {
+	tablet, err := NewTablet("root.ln.device1", []*MeasurementSchema{
+		{Measurement: "restart_count", DataType: INT32},
+		{Measurement: "description", DataType: TEXT},
+	}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for row := 0; row < 3; row++ {
+		tablet.SetTimestamp(int64(row+1), row)
+		tablet.SetValueAt(int32(row), 0, row)
+		tablet.SetValueAt("Test Device 1", 1, row)
+	}
+	tests := []struct {
+		name    string
+		maxRows int
+		want    string
+	}{
+		{
+			name:    "All rows",
+			maxRows: -1,
+			want: "Tablet root.ln.device1, 3 rows\n" +
+				"Time  restart_count(INT32)  description(TEXT)\n" +
+				"1     0                     \"Test Device 1\"\n" +
+				"2     1                     \"Test Device 1\"\n" +
+				"3     2                     \"Test Device 1\"\n",
+		}, {
+			name:    "Truncated",
+			maxRows: 1,
+			want: "Tablet root.ln.device1, 3 rows\n" +
+				"Time  restart_count(INT32)  description(TEXT)\n" +
+				"1     0                     \"Test Device 1\"\n" +
+				"... 2 more rows\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tablet.Format(tt.maxRows); got != tt.want {
+				t.Errorf("Tablet.Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTablet_getInsertMeasurements(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas []*MeasurementSchema
+		want    []string
+	}{
+		{
+			name: "Without alias",
+			schemas: []*MeasurementSchema{
+				{Measurement: "restart_count", DataType: INT32},
+				{Measurement: "temperature", DataType: FLOAT},
+			},
+			want: []string{"restart_count", "temperature"},
+		}, {
+			name: "With alias",
+			schemas: []*MeasurementSchema{
+				{Measurement: "restart_count", DataType: INT32},
+				{Measurement: "temperature", Alias: "temp", DataType: FLOAT},
+			},
+			want: []string{"restart_count", "temp"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, err := NewTablet("root.ln.device1", tt.schemas, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tablet.getInsertMeasurements(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tablet.getInsertMeasurements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTabletWithOptions(t *testing.T) {
+	schemas := []*MeasurementSchema{
+		{Measurement: "restart_count", DataType: INT32},
+		{Measurement: "broken", DataType: TSDataType(42)},
+		{Measurement: "temperature", DataType: FLOAT},
+	}
+	tests := []struct {
+		name             string
+		options          TabletOptions
+		wantErr          bool
+		wantMeasurements []string
+		wantSkipped      []int
+	}{
+		{
+			name:    "Strict",
+			options: TabletOptions{},
+			wantErr: true,
+		}, {
+			name:             "SkipInvalidColumns",
+			options:          TabletOptions{SkipInvalidColumns: true},
+			wantErr:          false,
+			wantMeasurements: []string{"restart_count", "temperature"},
+			wantSkipped:      []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, skipped, err := NewTabletWithOptions("root.ln.device1", schemas, 2, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTabletWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := tablet.GetMeasurements(); !reflect.DeepEqual(got, tt.wantMeasurements) {
+				t.Errorf("NewTabletWithOptions() measurements = %v, want %v", got, tt.wantMeasurements)
+			}
+			gotSkipped := make([]int, len(skipped))
+			for i, column := range skipped {
+				gotSkipped[i] = column.Index
+				if column.Reason == nil {
+					t.Errorf("NewTabletWithOptions() skipped column %d has no reason", column.Index)
+				}
+			}
+			if !reflect.DeepEqual(gotSkipped, tt.wantSkipped) {
+				t.Errorf("NewTabletWithOptions() skipped = %v, want %v", gotSkipped, tt.wantSkipped)
+			}
+			if len(tablet.values) != 2 {
+				t.Errorf("NewTabletWithOptions() values = %d columns, want 2", len(tablet.values))
+			}
+		})
+	}
+}
+
+func TestTablet_TimeRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		timestamps []int64
+		sort       bool
+		wantMin    int64
+		wantMax    int64
+		wantOk     bool
+	}{
+		{
+			name:       "Empty",
+			timestamps: []int64{},
+			wantOk:     false,
+		}, {
+			name:       "Unsorted",
+			timestamps: []int64{1608268702780, 1608268702769, 1608268702790},
+			wantMin:    1608268702769,
+			wantMax:    1608268702790,
+			wantOk:     true,
+		}, {
+			name:       "Sorted",
+			timestamps: []int64{1608268702780, 1608268702769, 1608268702790},
+			sort:       true,
+			wantMin:    1608268702769,
+			wantMax:    1608268702790,
+			wantOk:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, err := createTablet(len(tt.timestamps))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, timestamp := range tt.timestamps {
+				tablet.SetTimestamp(timestamp, i)
+			}
+			if tt.sort {
+				tablet.Sort()
+			}
+			gotMin, gotMax, gotOk := tablet.TimeRange()
+			if gotMin != tt.wantMin || gotMax != tt.wantMax || gotOk != tt.wantOk {
+				t.Errorf("Tablet.TimeRange() = %v, %v, %v, want %v, %v, %v", gotMin, gotMax, gotOk, tt.wantMin, tt.wantMax, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestTablet_Sort_negativeTimestamps(t *testing.T) {
+	tests := []struct {
+		name           string
+		timestamps     []int64
+		wantTimestamps []int64
+		wantValues     []int64
+	}{
+		{
+			name:           "Negative and zero",
+			timestamps:     []int64{0, -1608268702769, 1608268702769, -1},
+			wantTimestamps: []int64{-1608268702769, -1, 0, 1608268702769},
+			wantValues:     []int64{1, 3, 0, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, err := createTablet(len(tt.timestamps))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, timestamp := range tt.timestamps {
+				tablet.SetTimestamp(timestamp, i)
+				tablet.SetValueAt(int64(i), 2, i)
+				tablet.SetValueAt(int64ToString(int64(i)), 4, i)
+			}
+			if err := tablet.Sort(); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tablet.timestamps, tt.wantTimestamps) {
+				t.Errorf("Tablet.Sort() timestamps = %v, want %v", tablet.timestamps, tt.wantTimestamps)
+			}
+			for row, want := range tt.wantValues {
+				if got, _ := tablet.GetValueAt(2, row); got != want {
+					t.Errorf("Tablet.Sort() row %d value = %v, want %v", row, got, want)
+				}
+				if got, _ := tablet.GetValueAt(4, row); got != int64ToString(want) {
+					t.Errorf("Tablet.Sort() row %d text = %v, want %v", row, got, int64ToString(want))
+				}
+			}
+		})
+	}
+}
+
+func TestTablet_GetTimestampBytes_negativeTimestamps(t *testing.T) {
+	tablet, err := createTablet(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tablet.SetTimestamp(-1, 0)
+	tablet.SetTimestamp(0, 1)
+	tablet.SetTimestamp(-1608268702769, 2)
+	want := []byte{255, 255, 255, 255, 255, 255, 255, 255, 0, 0, 0, 0, 0, 0, 0, 0, 255, 255, 254, 137, 139, 183, 27, 207}
+	got := tablet.GetTimestampBytes()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tablet.GetTimestampBytes() = %v, want %v", got, want)
+	}
+	if bytesToInt64(got[16:]) != -1608268702769 {
+		t.Errorf("Tablet.GetTimestampBytes() doesn't round trip, got %d", bytesToInt64(got[16:]))
+	}
+}
+
+func TestTablet_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		required bool
+		setRows  []int
+		wantErr  bool
+	}{
+		{
+			name:     "Optional column with unset cells",
+			required: false,
+			setRows:  []int{0},
+			wantErr:  false,
+		}, {
+			name:     "Required column fully set",
+			required: true,
+			setRows:  []int{0, 1, 2},
+			wantErr:  false,
+		}, {
+			name:     "Required column with unset cells",
+			required: true,
+			setRows:  []int{0, 2},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, err := NewTablet("root.ln.device1", []*MeasurementSchema{
+				{Measurement: "temperature", DataType: FLOAT, Required: tt.required},
+			}, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range tt.setRows {
+				tablet.SetValueAt(float32(36.5), 0, row)
+			}
+			if err := tablet.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Tablet.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTablet_AddValidator(t *testing.T) {
+	inRange := func(rowIndex int, ts int64, values []interface{}) error {
+		if v, ok := values[0].(float32); ok && (v < -50 || v > 150) {
+			return fmt.Errorf("temperature %v out of range", v)
+		}
+		return nil
+	}
+	tests := []struct {
+		name     string
+		policy   ValidationPolicy
+		values   []float32
+		wantRows []int
+	}{
+		{
+			name:     "All rows valid",
+			policy:   ValidationFailFast,
+			values:   []float32{20, 36.5, 150},
+			wantRows: nil,
+		}, {
+			name:     "Fail fast",
+			policy:   ValidationFailFast,
+			values:   []float32{20, 200, -60},
+			wantRows: []int{1},
+		}, {
+			name:     "Collect all",
+			policy:   ValidationCollectAll,
+			values:   []float32{20, 200, -60},
+			wantRows: []int{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, err := NewTablet("root.ln.device1", []*MeasurementSchema{
+				{Measurement: "temperature", DataType: FLOAT},
+			}, len(tt.values))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for row, value := range tt.values {
+				tablet.SetTimestamp(int64(row), row)
+				tablet.SetValueAt(value, 0, row)
+			}
+			tablet.AddValidator(inRange)
+			tablet.SetValidationPolicy(tt.policy)
+			err = tablet.Validate()
+			if tt.wantRows == nil {
+				if err != nil {
+					t.Errorf("Tablet.Validate() error = %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Tablet.Validate() error = %v, want a *ValidationError", err)
+			}
+			var rows []int
+			for _, violation := range validationErr.Violations {
+				rows = append(rows, violation.RowIndex)
+			}
+			if !reflect.DeepEqual(rows, tt.wantRows) {
+				t.Errorf("rejected rows = %v, want %v", rows, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestTablet_Sort_keepsSetCells(t *testing.T) {
+	tablet, err := NewTablet("root.ln.device1", []*MeasurementSchema{
+		{Measurement: "temperature", DataType: FLOAT, Required: true},
+	}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tablet.SetTimestamp(2, 0)
+	tablet.SetTimestamp(1, 1)
+	tablet.SetValueAt(float32(36.5), 0, 0)
+	if err := tablet.Sort(); err != nil {
+		t.Fatal(err)
+	}
+	if tablet.isSet(0, 0) || !tablet.isSet(0, 1) {
+		t.Errorf("Tablet.Sort() didn't move the set cell along with its row")
+	}
+	if got := tablet.Format(-1); got != "Tablet root.ln.device1, 2 rows\nTime  temperature(FLOAT)\n1     <unset:0>\n2     36.5\n" {
+		t.Errorf("Tablet.Format() = %q", got)
+	}
+}
+
+func TestTablet_Append(t *testing.T) {
+	schemas := []*MeasurementSchema{
+		{Measurement: "restart_count", DataType: INT32},
+		{Measurement: "description", DataType: TEXT},
+	}
+	newTablet := func(deviceId string, schemas []*MeasurementSchema, timestamps ...int64) *Tablet {
+		tablet, _ := NewTablet(deviceId, schemas, len(timestamps))
+		for i, timestamp := range timestamps {
+			tablet.SetTimestamp(timestamp, i)
+			tablet.SetValueAt(int32(timestamp), 0, i)
+			if len(schemas) > 1 {
+				tablet.SetValueAt(int64ToString(timestamp), 1, i)
+			}
+		}
+		return tablet
+	}
+	tests := []struct {
+		name           string
+		other          *Tablet
+		wantErr        bool
+		wantTimestamps []int64
+	}{
+		{
+			name:           "Same schema",
+			other:          newTablet("root.ln.device1", schemas, 3, 4),
+			wantErr:        false,
+			wantTimestamps: []int64{1, 2, 3, 4},
+		}, {
+			name:    "Other device",
+			other:   newTablet("root.ln.device2", schemas, 3),
+			wantErr: true,
+		}, {
+			name:    "Other schema",
+			other:   newTablet("root.ln.device1", []*MeasurementSchema{{Measurement: "restart_count", DataType: INT32}}, 3),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet := newTablet("root.ln.device1", schemas, 1, 2)
+			if err := tablet.Append(tt.other); (err != nil) != tt.wantErr {
+				t.Fatalf("Tablet.Append() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(tablet.timestamps, tt.wantTimestamps) || tablet.GetRowCount() != len(tt.wantTimestamps) {
+				t.Errorf("Tablet.Append() timestamps = %v, want %v", tablet.timestamps, tt.wantTimestamps)
+			}
+			if got, _ := tablet.GetValueAt(1, 3); got != "4" {
+				t.Errorf("Tablet.Append() value = %v, want 4", got)
+			}
+			if err := tablet.Validate(); err != nil || !tablet.isSet(0, 3) {
+				t.Errorf("Tablet.Append() didn't keep the set cells")
+			}
+		})
+	}
+}
+
+func TestTablet_MergeColumns(t *testing.T) {
+	newTablet := func(deviceId string, measurement string, timestamps ...int64) *Tablet {
+		tablet, _ := NewTablet(deviceId, []*MeasurementSchema{{Measurement: measurement, DataType: INT64}}, len(timestamps))
+		for i, timestamp := range timestamps {
+			tablet.SetTimestamp(timestamp, i)
+			tablet.SetValueAt(timestamp*10, 0, i)
+		}
+		return tablet
+	}
+	tests := []struct {
+		name    string
+		other   *Tablet
+		wantErr bool
+	}{
+		{
+			name:    "Same rows",
+			other:   newTablet("root.ln.device1", "tick_count", 1, 2),
+			wantErr: false,
+		}, {
+			name:    "Other device",
+			other:   newTablet("root.ln.device2", "tick_count", 1, 2),
+			wantErr: true,
+		}, {
+			name:    "Other timestamps",
+			other:   newTablet("root.ln.device1", "tick_count", 1, 3),
+			wantErr: true,
+		}, {
+			name:    "Other row count",
+			other:   newTablet("root.ln.device1", "tick_count", 1),
+			wantErr: true,
+		}, {
+			name:    "Duplicate measurement",
+			other:   newTablet("root.ln.device1", "restart_count", 1, 2),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet := newTablet("root.ln.device1", "restart_count", 1, 2)
+			merged, err := tablet.MergeColumns(tt.other)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tablet.MergeColumns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := merged.GetMeasurements(); !reflect.DeepEqual(got, []string{"restart_count", "tick_count"}) {
+				t.Errorf("Tablet.MergeColumns() measurements = %v", got)
+			}
+			if got, _ := merged.GetValueAt(1, 1); got != int64(20) {
+				t.Errorf("Tablet.MergeColumns() value = %v, want 20", got)
+			}
+			if len(tablet.measurementSchemas) != 1 {
+				t.Errorf("Tablet.MergeColumns() modified the tablet")
+			}
+		})
+	}
+}
+
+func TestTablet_MapColumn(t *testing.T) {
+	tests := []struct {
+		name        string
+		columnIndex int
+		fn          interface{}
+		want        interface{}
+		wantErr     bool
+	}{
+		{
+			name:        "DOUBLE calibration",
+			columnIndex: 1,
+			fn:          func(v float64) float64 { return v*2 + 1 },
+			want:        float64(5),
+			wantErr:     false,
+		}, {
+			name:        "TEXT",
+			columnIndex: 4,
+			fn:          func(v string) string { return v + "!" },
+			want:        "2!",
+			wantErr:     false,
+		}, {
+			name:        "Mismatched type",
+			columnIndex: 1,
+			fn:          func(v float32) float32 { return v },
+			wantErr:     true,
+		}, {
+			name:        "Not a function",
+			columnIndex: 1,
+			fn:          2.0,
+			wantErr:     true,
+		}, {
+			name:        "Illegal column",
+			columnIndex: 6,
+			fn:          func(v float64) float64 { return v },
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, _ := createTablet(2)
+			for row := 0; row < 2; row++ {
+				tablet.SetValueAt(float64(row+1), 1, row)
+				tablet.SetValueAt(int64ToString(int64(row+1)), 4, row)
+			}
+			if err := tablet.MapColumn(tt.columnIndex, tt.fn); (err != nil) != tt.wantErr {
+				t.Fatalf("Tablet.MapColumn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got, _ := tablet.GetValueAt(tt.columnIndex, 1); got != tt.want {
+				t.Errorf("Tablet.MapColumn() value = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTablet_AppendColumn(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  *MeasurementSchema
+		values  interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Derived column",
+			schema:  &MeasurementSchema{Measurement: "delta", DataType: DOUBLE},
+			values:  []float64{0.5, 1.5},
+			wantErr: false,
+		}, {
+			name:    "Duplicate measurement",
+			schema:  &MeasurementSchema{Measurement: "restart_count", DataType: DOUBLE},
+			values:  []float64{0.5, 1.5},
+			wantErr: true,
+		}, {
+			name:    "Wrong type",
+			schema:  &MeasurementSchema{Measurement: "delta", DataType: DOUBLE},
+			values:  []float32{0.5, 1.5},
+			wantErr: true,
+		}, {
+			name:    "Wrong length",
+			schema:  &MeasurementSchema{Measurement: "delta", DataType: DOUBLE},
+			values:  []float64{0.5},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, _ := NewTablet("root.ln.device1", []*MeasurementSchema{{Measurement: "restart_count", DataType: INT32}}, 2)
+			err := tablet.AppendColumn(tt.schema, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tablet.AppendColumn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if len(tablet.measurementSchemas) != 1 {
+					t.Errorf("Tablet.AppendColumn() modified the tablet")
+				}
+				return
+			}
+			if got := tablet.GetMeasurements(); !reflect.DeepEqual(got, []string{"restart_count", "delta"}) {
+				t.Errorf("Tablet.AppendColumn() measurements = %v", got)
+			}
+			if got, _ := tablet.GetValueAt(1, 1); got != 1.5 {
+				t.Errorf("Tablet.AppendColumn() value = %v, want 1.5", got)
+			}
+			tt.values.([]float64)[1] = 2.5
+			if got, _ := tablet.GetValueAt(1, 1); got != 1.5 {
+				t.Errorf("Tablet.AppendColumn() shares the values of the caller")
+			}
+			if !tablet.isSet(1, 0) || tablet.isSet(0, 0) {
+				t.Errorf("Tablet.AppendColumn() set cells are wrong")
+			}
+		})
+	}
+}
+
+func TestTablet_Checksum(t *testing.T) {
+	newTablet := func(text string, set bool) *Tablet {
+		tablet, _ := NewTablet("root.ln.device1", []*MeasurementSchema{
+			{Measurement: "restart_count", DataType: INT32},
+			{Measurement: "description", DataType: TEXT},
+		}, 2)
+		tablet.SetTimestamp(1, 0)
+		tablet.SetTimestamp(2, 1)
+		tablet.SetValueAt(int32(1), 0, 0)
+		tablet.SetValueAt(text, 1, 0)
+		if set {
+			tablet.SetValueAt(int32(0), 0, 1)
+		}
+		return tablet
+	}
+	tablet := newTablet("text", false)
+	// Computed independently from the documented byte layout.
+	if got, want := tablet.Checksum(), uint64(0x8b7a3da6ed2d5734); got != want {
+		t.Errorf("Tablet.Checksum() = %#x, want %#x", got, want)
+	}
+	if tablet.Checksum() != newTablet("text", false).Checksum() {
+		t.Errorf("Tablet.Checksum() differs for identical tablets")
+	}
+	if tablet.Checksum() == newTablet("texT", false).Checksum() {
+		t.Errorf("Tablet.Checksum() is the same for a different value")
+	}
+	if tablet.Checksum() == newTablet("text", true).Checksum() {
+		t.Errorf("Tablet.Checksum() is the same for a zero value and an unset cell")
+	}
+}
+
+func TestTablet_ToRecords(t *testing.T) {
+	tablet, _ := NewTablet("root.ln.device1", []*MeasurementSchema{
+		{Measurement: "restart_count", DataType: INT32},
+		{Measurement: "temperature", Alias: "temp", DataType: FLOAT},
+	}, 3)
+	tablet.SetTimestamp(1, 0)
+	tablet.SetTimestamp(2, 1)
+	tablet.SetTimestamp(3, 2)
+	tablet.SetValueAt(int32(1), 0, 0)
+	tablet.SetValueAt(float32(36.5), 1, 0)
+	tablet.SetValueAt(float32(37), 1, 2)
+
+	got, err := tablet.ToRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Records{
+		DeviceIds:    []string{"root.ln.device1", "root.ln.device1"},
+		Measurements: [][]string{{"restart_count", "temp"}, {"temp"}},
+		DataTypes:    [][]TSDataType{{INT32, FLOAT}, {FLOAT}},
+		Values:       [][]interface{}{{int32(1), float32(36.5)}, {float32(37)}},
+		Timestamps:   []int64{1, 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tablet.ToRecords() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTablet_reordered(t *testing.T) {
+	tablet, err := NewTablet("root.ln.device1", []*MeasurementSchema{
+		{Measurement: "restart_count", DataType: INT32},
+		{Measurement: "description", DataType: TEXT},
+		{Measurement: "status", DataType: BOOLEAN},
+	}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for row := 0; row < 2; row++ {
+		tablet.SetTimestamp(int64(row), row)
+		tablet.SetValueAt(int32(row), 0, row)
+		tablet.SetValueAt(fmt.Sprintf("row %d", row), 1, row)
+		tablet.SetValueAt(row == 0, 2, row)
+	}
+	before, _ := tablet.getValuesBytes()
+
+	order, err := tablet.ColumnOrder("status", "restart_count", "description")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(order, []int{2, 0, 1}) {
+		t.Errorf("Tablet.ColumnOrder() = %v, want [2 0 1]", order)
+	}
+	ordered, err := tablet.reordered(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ordered.getInsertMeasurements(); !reflect.DeepEqual(got, []string{"status", "restart_count", "description"}) {
+		t.Errorf("reordered measurements = %v", got)
+	}
+	if got := ordered.getDataTypes(); !reflect.DeepEqual(got, []int32{int32(BOOLEAN), int32(INT32), int32(TEXT)}) {
+		t.Errorf("reordered types = %v", got)
+	}
+	want := []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	want = append(want, 0, 0, 0, 5, 'r', 'o', 'w', ' ', '0', 0, 0, 0, 5, 'r', 'o', 'w', ' ', '1')
+	if got, _ := ordered.getValuesBytes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("reordered values = %v, want %v", got, want)
+	}
+	if after, _ := tablet.getValuesBytes(); !reflect.DeepEqual(after, before) {
+		t.Error("Tablet.reordered() changed the tablet")
+	}
+
+	if _, err := tablet.ColumnOrder("temperature"); err == nil {
+		t.Error("Tablet.ColumnOrder() accepted an unknown measurement")
+	}
+	for _, order := range [][]int{{0, 1}, {0, 0, 1}, {0, 1, 3}} {
+		if _, err := tablet.reordered(order); err == nil {
+			t.Errorf("Tablet.reordered(%v) accepted an illegal order", order)
+		}
+	}
+}
+
+func TestTablet_ShiftTime(t *testing.T) {
+	tests := []struct {
+		name       string
+		timestamps []int64
+		offset     int64
+		want       []int64
+		wantErr    bool
+	}{
+		{
+			name:       "Forward",
+			timestamps: []int64{-5, 0, 10},
+			offset:     100,
+			want:       []int64{95, 100, 110},
+		}, {
+			name:       "Backward",
+			timestamps: []int64{-5, 0, 10},
+			offset:     -10,
+			want:       []int64{-15, -10, 0},
+		}, {
+			name:       "Overflow",
+			timestamps: []int64{0, math.MaxInt64 - 1},
+			offset:     2,
+			want:       []int64{0, math.MaxInt64 - 1},
+			wantErr:    true,
+		}, {
+			name:       "Underflow",
+			timestamps: []int64{math.MinInt64 + 1, 0},
+			offset:     -2,
+			want:       []int64{math.MinInt64 + 1, 0},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, _ := NewTablet("root.ln.device1", []*MeasurementSchema{{Measurement: "status", DataType: BOOLEAN}}, len(tt.timestamps))
+			copy(tablet.timestamps, tt.timestamps)
+			if err := tablet.ShiftTime(tt.offset); (err != nil) != tt.wantErr {
+				t.Errorf("Tablet.ShiftTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tablet.timestamps, tt.want) {
+				t.Errorf("Tablet.ShiftTime() timestamps = %v, want %v", tablet.timestamps, tt.want)
+			}
+		})
+	}
+}
+
+func TestTablet_RescaleTime(t *testing.T) {
+	tests := []struct {
+		name       string
+		timestamps []int64
+		from, to   string
+		want       []int64
+		wantErr    bool
+	}{
+		{
+			name:       "Milliseconds to nanoseconds",
+			timestamps: []int64{-1, 0, 1621405200000},
+			from:       TimePrecisionMillisecond,
+			to:         TimePrecisionNanosecond,
+			want:       []int64{-1000000, 0, 1621405200000000000},
+		}, {
+			name:       "Microseconds to milliseconds",
+			timestamps: []int64{-1, 1999, 2000},
+			from:       TimePrecisionMicrosecond,
+			to:         TimePrecisionMillisecond,
+			want:       []int64{-1, 1, 2},
+		}, {
+			name:       "Same precision",
+			timestamps: []int64{7},
+			from:       TimePrecisionNanosecond,
+			to:         TimePrecisionNanosecond,
+			want:       []int64{7},
+		}, {
+			name:       "Overflow",
+			timestamps: []int64{0, math.MaxInt64 / 1000},
+			from:       TimePrecisionMillisecond,
+			to:         TimePrecisionNanosecond,
+			want:       []int64{0, math.MaxInt64 / 1000},
+			wantErr:    true,
+		}, {
+			name:       "Illegal precision",
+			timestamps: []int64{7},
+			from:       "s",
+			to:         TimePrecisionNanosecond,
+			want:       []int64{7},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, _ := NewTablet("root.ln.device1", []*MeasurementSchema{{Measurement: "status", DataType: BOOLEAN}}, len(tt.timestamps))
+			copy(tablet.timestamps, tt.timestamps)
+			if err := tablet.RescaleTime(tt.from, tt.to); (err != nil) != tt.wantErr {
+				t.Errorf("Tablet.RescaleTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tablet.timestamps, tt.want) {
+				t.Errorf("Tablet.RescaleTime() timestamps = %v, want %v", tablet.timestamps, tt.want)
+			}
+		})
+	}
+}
+
+func TestTablet_AppendRows(t *testing.T) {
+	schemas := []*MeasurementSchema{
+		{Measurement: "restart_count", DataType: INT32},
+		{Measurement: "description", DataType: TEXT},
+	}
+	tests := []struct {
+		name       string
+		timestamps []int64
+		columns    []interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "Rows",
+			timestamps: []int64{3, 4},
+			columns:    []interface{}{[]int32{30, 40}, []string{"c", "d"}},
+		}, {
+			name:       "Missing column",
+			timestamps: []int64{3, 4},
+			columns:    []interface{}{[]int32{30, 40}},
+			wantErr:    true,
+		}, {
+			name:       "Wrong type",
+			timestamps: []int64{3, 4},
+			columns:    []interface{}{[]int64{30, 40}, []string{"c", "d"}},
+			wantErr:    true,
+		}, {
+			name:       "Short column",
+			timestamps: []int64{3, 4},
+			columns:    []interface{}{[]int32{30, 40}, []string{"c"}},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tablet, _ := NewTablet("root.ln.device1", schemas, 0)
+			if err := tablet.AppendRows([]int64{1, 2}, []interface{}{[]int32{10, 20}, []string{"a", "b"}}); err != nil {
+				t.Fatal(err)
+			}
+			err := tablet.AppendRows(tt.timestamps, tt.columns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tablet.AppendRows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			wantRows := 2
+			if !tt.wantErr {
+				wantRows = 4
+			}
+			if tablet.GetRowCount() != wantRows || len(tablet.timestamps) != wantRows {
+				t.Fatalf("Tablet.AppendRows() rows = %d, want %d", tablet.GetRowCount(), wantRows)
+			}
+			if err := tablet.Validate(); err != nil || !tablet.isSet(1, wantRows-1) {
+				t.Errorf("Tablet.AppendRows() left unset cells: %v", err)
+			}
+			if value, _ := tablet.GetValueAt(1, wantRows-1); !tt.wantErr && value != "d" {
+				t.Errorf("Tablet.AppendRows() last description = %v, want d", value)
+			}
+		})
+	}
+}
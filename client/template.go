@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+// The columns of the schema template statements.
+const (
+	templateNameColumnName = "template name"
+	childNodesColumnName   = "child nodes"
+	childPathsColumnName   = "child paths"
+)
+
+// ShowSchemaTemplates returns the names of the schema templates of the server.
+// The client has no RPC for the templates, they are read with SQL statements
+// which servers before 0.13 don't know.
+func (s *Session) ShowSchemaTemplates() ([]string, error) {
+	return s.showTexts("show schema templates", templateNameColumnName)
+}
+
+// ShowNodesInTemplate returns the measurements of a schema template, their
+// Encoding and Compressor are -1 for the names the client doesn't know.
+func (s *Session) ShowNodesInTemplate(name string) ([]MeasurementSchema, error) {
+	if err := ValidateIdentifier(name); err != nil {
+		return nil, err
+	}
+	dataSet, err := s.executeQuery("show nodes in schema template " + name)
+	if err != nil {
+		return nil, err
+	}
+	defer dataSet.Close()
+	return readTemplateNodes(dataSet)
+}
+
+// ShowPathsTemplateSetOn returns the paths a schema template is set on.
+func (s *Session) ShowPathsTemplateSetOn(name string) ([]string, error) {
+	if err := ValidateIdentifier(name); err != nil {
+		return nil, err
+	}
+	return s.showTexts("show paths set schema template "+name, childPathsColumnName)
+}
+
+func (s *Session) showTexts(sql string, columnName string) ([]string, error) {
+	dataSet, err := s.executeQuery(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer dataSet.Close()
+	return readTexts(dataSet, columnName)
+}
+
+func readTexts(dataSet *SessionDataSet, columnName string) ([]string, error) {
+	texts := make([]string, 0)
+	for {
+		hasNext, err := dataSet.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !hasNext {
+			return texts, nil
+		}
+		texts = append(texts, dataSet.GetText(columnName))
+	}
+}
+
+func readTemplateNodes(dataSet *SessionDataSet) ([]MeasurementSchema, error) {
+	schemas := make([]MeasurementSchema, 0)
+	for {
+		hasNext, err := dataSet.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !hasNext {
+			return schemas, nil
+		}
+		schema := MeasurementSchema{
+			Measurement: dataSet.GetText(childNodesColumnName),
+			DataType:    UNKNOW,
+			Encoding:    -1,
+			Compressor:  -1,
+		}
+		if dataType, ok := tsTypeMap[dataSet.GetText(dataTypeColumnName)]; ok {
+			schema.DataType = dataType
+		}
+		if encoding, ok := tsEncodingMap[dataSet.GetText(encodingColumnName)]; ok {
+			schema.Encoding = encoding
+		}
+		if compressor, ok := tsCompressionMap[dataSet.GetText(compressionColumnName)]; ok {
+			schema.Compressor = compressor
+		}
+		schemas = append(schemas, schema)
+	}
+}
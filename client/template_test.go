@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func Test_readTemplateNodes(t *testing.T) {
+	texts := func(values ...string) []byte {
+		buff := make([]byte, 0)
+		for _, s := range values {
+			buff = append(append(buff, int32ToBytes(int32(len(s)))...), s...)
+		}
+		return buff
+	}
+	data := rpc.TSQueryDataSet{
+		Time: make([]byte, 16),
+		ValueList: [][]byte{
+			texts("temperature", "status"),
+			texts("FLOAT", "BOOLEAN"),
+			texts("GORILLA", "FREQ"),
+			texts("SNAPPY", "ZSTD"),
+		},
+		BitmapList: [][]byte{{192}, {192}, {192}, {192}},
+	}
+	columns := []string{childNodesColumnName, dataTypeColumnName, encodingColumnName, compressionColumnName}
+	ds := NewSessionDataSet("", columns, []string{"TEXT", "TEXT", "TEXT", "TEXT"}, nil, 1, nil, 1, &data, true, DefaultFetchSize)
+	ds.ioTDBRpcDataSet.emptyResultSet = true
+	got, err := readTemplateNodes(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MeasurementSchema{
+		{Measurement: "temperature", DataType: FLOAT, Encoding: GORILLA, Compressor: SNAPPY},
+		{Measurement: "status", DataType: BOOLEAN, Encoding: -1, Compressor: -1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readTemplateNodes() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_readTexts(t *testing.T) {
+	data := rpc.TSQueryDataSet{
+		Time:       make([]byte, 16),
+		ValueList:  [][]byte{append(append(int32ToBytes(2), "t1"...), append(int32ToBytes(2), "t2"...)...)},
+		BitmapList: [][]byte{{192}},
+	}
+	ds := NewSessionDataSet("", []string{templateNameColumnName}, []string{"TEXT"}, nil, 1, nil, 1, &data, true, DefaultFetchSize)
+	ds.ioTDBRpcDataSet.emptyResultSet = true
+	got, err := readTexts(ds, templateNameColumnName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []string{"t1", "t2"}) {
+		t.Errorf("readTexts() = %v, want [t1 t2]", got)
+	}
+}
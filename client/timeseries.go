@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Point is one aggregated window of a time series, Value is nil for a window
+// without data unless a fill value is used.
+type Point struct {
+	Time  int64
+	Value interface{}
+}
+
+// QueryTimeSeries aggregates a time series over [startTime, endTime) in windows
+// of interval, with an aggregation function such as avg, max_value or count, and
+// returns one Point per window. Windows without data have a nil Value. The
+// times and the interval are in the time precision of the session,
+// Config.TimePrecision, milliseconds if it is unknown.
+func (s *Session) QueryTimeSeries(path string, startTime, endTime, interval int64, agg string) ([]Point, error) {
+	return s.QueryTimeSeriesWithFill(path, startTime, endTime, interval, agg, nil)
+}
+
+// QueryTimeSeriesWithFill is QueryTimeSeries using fill as the Value of the
+// windows without data.
+func (s *Session) QueryTimeSeriesWithFill(path string, startTime, endTime, interval int64, agg string, fill interface{}) ([]Point, error) {
+	sql, err := groupByStatement(path, startTime, endTime, interval, s.config.TimePrecision, agg)
+	if err != nil {
+		return nil, err
+	}
+	dataSet, err := s.executeQuery(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer dataSet.Close()
+	return readPoints(dataSet, fill)
+}
+
+// groupByStatement sends interval with the unit of precision, the server
+// converts it to its own precision.
+func groupByStatement(path string, startTime, endTime, interval int64, precision, agg string) (string, error) {
+	segments := SplitPath(path)
+	if len(segments) < 2 {
+		return "", fmt.Errorf("%s is not a time series path", path)
+	}
+	if interval <= 0 {
+		return "", errors.New("interval must be positive")
+	}
+	if endTime <= startTime {
+		return "", errors.New("endTime must be greater than startTime")
+	}
+	if !isFunctionName(agg) {
+		return "", fmt.Errorf("illegal aggregation function %s", agg)
+	}
+	if precision == "" {
+		precision = TimePrecisionMillisecond
+	} else if _, err := precisionUnit(precision); err != nil {
+		return "", err
+	}
+	device := BuildPath(segments[:len(segments)-1]...)
+	measurement := BuildPath(segments[len(segments)-1])
+	return fmt.Sprintf("select %s(%s) from %s group by ([%d, %d), %d%s)", agg, measurement, device, startTime, endTime, interval, precision), nil
+}
+
+func isFunctionName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func readPoints(dataSet *SessionDataSet, fill interface{}) ([]Point, error) {
+	if dataSet.GetColumnCount() != 1 {
+		return nil, fmt.Errorf("expected one aggregation column, got %d", dataSet.GetColumnCount())
+	}
+	columnName := dataSet.GetColumnName(0)
+	points := make([]Point, 0)
+	for {
+		hasNext, err := dataSet.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !hasNext {
+			return points, nil
+		}
+		value := dataSet.GetValue(columnName)
+		if value == nil {
+			value = fill
+		}
+		points = append(points, Point{Time: dataSet.GetTimestamp(), Value: value})
+	}
+}
+
+// CountPoints returns the number of points of a time series in
+// [startTime, endTime). The path may contain wildcards, root.sg.*.s1 or
+// root.sg.d1.*, the points of all the matched series are then added up. A path
+// without data, or matching no series, has 0 points.
+func (s *Session) CountPoints(path string, startTime, endTime int64) (int64, error) {
+	sql, err := countStatement(path, startTime, endTime)
+	if err != nil {
+		return 0, err
+	}
+	dataSet, err := s.executeQuery(sql)
+	if err != nil {
+		return 0, err
+	}
+	defer dataSet.Close()
+	return sumCounts(dataSet)
+}
+
+func countStatement(path string, startTime, endTime int64) (string, error) {
+	segments := SplitPath(path)
+	if len(segments) < 2 {
+		return "", fmt.Errorf("%s is not a time series path", path)
+	}
+	return Select(Count(Column(BuildPath(segments[len(segments)-1])))).
+		From(BuildPath(segments[:len(segments)-1]...)).
+		Where(TimeRange(startTime, endTime)).
+		Build()
+}
+
+// sumCounts adds up the count columns of the single row of a count query.
+func sumCounts(dataSet *SessionDataSet) (int64, error) {
+	hasNext, err := dataSet.Next()
+	if err != nil || !hasNext {
+		return 0, err
+	}
+	var total int64
+	for _, columnName := range dataSet.GetColumnNames() {
+		if count, ok := dataSet.GetValue(columnName).(int64); ok {
+			total += count
+		}
+	}
+	return total, nil
+}
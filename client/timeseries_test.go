@@ -0,0 +1,178 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func Test_groupByStatement(t *testing.T) {
+	type args struct {
+		path      string
+		startTime int64
+		endTime   int64
+		interval  int64
+		precision string
+		agg       string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Avg",
+			args: args{path: "root.ln.device1.temperature", startTime: 0, endTime: 3600000, interval: 60000, precision: TimePrecisionMillisecond, agg: "avg"},
+			want: "select avg(temperature) from root.ln.device1 group by ([0, 3600000), 60000ms)",
+		}, {
+			name: "Microseconds",
+			args: args{path: "root.ln.device1.temperature", startTime: 0, endTime: 3600000000, interval: 1500, precision: TimePrecisionMicrosecond, agg: "avg"},
+			want: "select avg(temperature) from root.ln.device1 group by ([0, 3600000000), 1500us)",
+		}, {
+			name: "Unknown precision",
+			args: args{path: "root.ln.device1.temperature", startTime: 0, endTime: 3600000, interval: 60000, agg: "avg"},
+			want: "select avg(temperature) from root.ln.device1 group by ([0, 3600000), 60000ms)",
+		}, {
+			name: "Quoted device",
+			args: args{path: "root.ln.`device.1`.temperature", startTime: 0, endTime: 10, interval: 5, precision: TimePrecisionMillisecond, agg: "max_value"},
+			want: "select max_value(temperature) from root.ln.`device.1` group by ([0, 10), 5ms)",
+		}, {
+			name:    "Illegal function",
+			args:    args{path: "root.ln.device1.temperature", startTime: 0, endTime: 10, interval: 5, precision: TimePrecisionMillisecond, agg: "avg(x)"},
+			wantErr: true,
+		}, {
+			name:    "Illegal precision",
+			args:    args{path: "root.ln.device1.temperature", startTime: 0, endTime: 10, interval: 5, precision: "s", agg: "avg"},
+			wantErr: true,
+		}, {
+			name:    "Empty range",
+			args:    args{path: "root.ln.device1.temperature", startTime: 10, endTime: 10, interval: 5, precision: TimePrecisionMillisecond, agg: "avg"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := groupByStatement(tt.args.path, tt.args.startTime, tt.args.endTime, tt.args.interval, tt.args.precision, tt.args.agg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("groupByStatement() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("groupByStatement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readPoints(t *testing.T) {
+	queryDataSet := rpc.TSQueryDataSet{
+		Time:       append(int64ToBytes(0), append(int64ToBytes(5), int64ToBytes(10)...)...),
+		ValueList:  [][]byte{append(int64ToBytes(4), int64ToBytes(6)...)},
+		BitmapList: [][]byte{{160}},
+	}
+	tests := []struct {
+		name string
+		fill interface{}
+		want []Point
+	}{
+		{
+			name: "Nil",
+			fill: nil,
+			want: []Point{{0, int64(4)}, {5, nil}, {10, int64(6)}},
+		}, {
+			name: "Fill",
+			fill: int64(0),
+			want: []Point{{0, int64(4)}, {5, int64(0)}, {10, int64(6)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := queryDataSet
+			data.ValueList = [][]byte{append([]byte{}, queryDataSet.ValueList[0]...)}
+			data.BitmapList = [][]byte{{160}}
+			ds := NewSessionDataSet("", []string{"count(root.ln.device1.status)"}, []string{"INT64"}, nil, 1, nil, 1, &data, false, DefaultFetchSize)
+			ds.ioTDBRpcDataSet.emptyResultSet = true
+			got, err := readPoints(ds, tt.fill)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readPoints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_countStatement(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Series",
+			path: "root.ln.device1.status",
+			want: "select count(status) from root.ln.device1 where time >= 0 and time < 10",
+		}, {
+			name: "Wildcard",
+			path: "root.ln.*.*",
+			want: "select count(*) from root.ln.* where time >= 0 and time < 10",
+		}, {
+			name:    "Not a series",
+			path:    "root",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := countStatement(tt.path, 0, 10)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("countStatement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("countStatement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sumCounts(t *testing.T) {
+	data := rpc.TSQueryDataSet{
+		Time:       []byte{0, 0, 0, 0, 0, 0, 0, 0},
+		ValueList:  [][]byte{{0, 0, 0, 0, 0, 0, 0, 4}, {0, 0, 0, 0, 0, 0, 0, 6}},
+		BitmapList: [][]byte{{128}, {128}},
+	}
+	ds := NewSessionDataSet("", []string{"count(root.ln.device1.status)", "count(root.ln.device2.status)"}, []string{"INT64", "INT64"}, nil, 1, nil, 1, &data, true, DefaultFetchSize)
+	ds.ioTDBRpcDataSet.emptyResultSet = true
+	if got, err := sumCounts(ds); err != nil || got != 10 {
+		t.Errorf("sumCounts() = %v, %v, want 10", got, err)
+	}
+
+	empty := NewSessionDataSet("", []string{}, []string{}, nil, 1, nil, 1, &rpc.TSQueryDataSet{}, true, DefaultFetchSize)
+	empty.ioTDBRpcDataSet.emptyResultSet = true
+	if got, err := sumCounts(empty); err != nil || got != 0 {
+		t.Errorf("sumCounts() of no series = %v, %v, want 0", got, err)
+	}
+}
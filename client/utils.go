@@ -95,6 +95,18 @@ func VerifySuccess(status *rpc.TSStatus) error {
 		}
 		return nil
 	}
+	if status.Code == ReadOnlySystemError {
+		if status.Message != nil {
+			return fmt.Errorf("%w: %v", ErrSystemReadOnly, *status.Message)
+		}
+		return ErrSystemReadOnly
+	}
+	if status.Code == WriteProcessReject {
+		if status.Message != nil {
+			return fmt.Errorf("%w: %v", ErrWriteRejected, *status.Message)
+		}
+		return ErrWriteRejected
+	}
 	if status.Code != SuccessStatus {
 		if status.Message != nil {
 			return fmt.Errorf("Error Code: %d, Message: %v", status.Code, *status.Message)
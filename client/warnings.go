@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+
+	"github.com/apache/iotdb-client-go/rpc"
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// genericSuccessMessage is set by the server on successful statements, it
+// isn't a warning.
+const genericSuccessMessage = "Execute successfully"
+
+// Warnings returns the messages the server attached to the successful parts of
+// a status, such as a time series auto-created with an inferred type. The
+// failed parts are reported by VerifySuccess instead.
+func Warnings(status *rpc.TSStatus) []string {
+	if status == nil {
+		return nil
+	}
+	var warnings []string
+	if status.Code == SuccessStatus && status.Message != nil && *status.Message != "" && *status.Message != genericSuccessMessage {
+		warnings = append(warnings, *status.Message)
+	}
+	for _, subStatus := range status.GetSubStatus() {
+		warnings = append(warnings, Warnings(subStatus)...)
+	}
+	return warnings
+}
+
+// warningClient logs the warnings of the statuses returned by the server, see
+// Config.LogWarnings.
+type warningClient struct {
+	client thrift.TClient
+	logf   func(format string, v ...interface{})
+}
+
+func (c *warningClient) Call(ctx context.Context, method string, args, result thrift.TStruct) error {
+	err := c.client.Call(ctx, method, args, result)
+	if err != nil {
+		return err
+	}
+	if statusResult, ok := result.(interface{ GetSuccess() *rpc.TSStatus }); ok {
+		for _, warning := range Warnings(statusResult.GetSuccess()) {
+			c.logf("server warning in %s: %s", method, warning)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func TestWarnings(t *testing.T) {
+	message := func(s string) *string {
+		return &s
+	}
+	tests := []struct {
+		name   string
+		status *rpc.TSStatus
+		want   []string
+	}{
+		{
+			name:   "Nil",
+			status: nil,
+			want:   nil,
+		}, {
+			name:   "Plain success",
+			status: &rpc.TSStatus{Code: SuccessStatus, Message: message(genericSuccessMessage)},
+			want:   nil,
+		}, {
+			name:   "Success with warning",
+			status: &rpc.TSStatus{Code: SuccessStatus, Message: message("auto-created timeseries root.sg.d1.s1 as DOUBLE")},
+			want:   []string{"auto-created timeseries root.sg.d1.s1 as DOUBLE"},
+		}, {
+			name: "Sub-statuses",
+			status: &rpc.TSStatus{Code: MultipleError, SubStatus: []*rpc.TSStatus{
+				{Code: SuccessStatus},
+				{Code: SuccessStatus, Message: message("auto-created timeseries root.sg.d1.s2 as INT64")},
+				{Code: WriteProcessError, Message: message("write failed")},
+			}},
+			want: []string{"auto-created timeseries root.sg.d1.s2 as INT64"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Warnings(tt.status); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Warnings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// statusClient answers every call returning a TSStatus with status.
+type statusClient struct {
+	status *rpc.TSStatus
+}
+
+func (c *statusClient) Call(ctx context.Context, method string, args, result thrift.TStruct) error {
+	if r, ok := result.(*rpc.TSIServiceInsertTabletResult); ok {
+		r.Success = c.status
+	}
+	return nil
+}
+
+func TestWarningClient(t *testing.T) {
+	warning := "auto-created timeseries root.sg.d1.s1 as DOUBLE"
+	logger := &recordingLogger{}
+	status := &rpc.TSStatus{Code: SuccessStatus, SubStatus: []*rpc.TSStatus{{Code: SuccessStatus, Message: &warning}}}
+	client := rpc.NewTSIServiceClient(&warningClient{client: &statusClient{status: status}, logf: logger.Printf})
+
+	r, err := client.InsertTablet(context.Background(), &rpc.TSInsertTabletReq{})
+	if err != nil || VerifySuccess(r) != nil {
+		t.Fatalf("InsertTablet() = %v, %v", r, err)
+	}
+	if want := []string{"server warning in insertTablet: " + warning}; !reflect.DeepEqual(logger.messages, want) {
+		t.Errorf("warningClient logged %v, want %v", logger.messages, want)
+	}
+}
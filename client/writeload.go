@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+// WriteLoadHint tells whether the server asked the client to slow down.
+type WriteLoadHint int
+
+const (
+	// WriteLoadNormal means the server accepted the write, or failed it for
+	// another reason than its load.
+	WriteLoadNormal WriteLoadHint = iota
+	// WriteLoadOverloaded means the server rejected the write because it is
+	// short of memory for its memtables, the write should be retried later
+	// at a lower rate.
+	WriteLoadOverloaded
+)
+
+// ErrWriteRejected is wrapped by VerifySuccess for a WriteProcessReject status.
+var ErrWriteRejected = errors.New("the server rejected the write, it is overloaded")
+
+// GetWriteLoadHint returns the load signal carried by the status of an insert.
+//
+// The only backpressure signal of the server is the WriteProcessReject (413)
+// status code, returned by the 0.1x releases this client targets when their
+// write memory is exhausted; the responses carry no memory usage figure. The
+// hint is WriteLoadNormal for servers which never send it, and for a nil
+// status.
+func GetWriteLoadHint(status *rpc.TSStatus) WriteLoadHint {
+	if status == nil {
+		return WriteLoadNormal
+	}
+	if status.Code == WriteProcessReject {
+		return WriteLoadOverloaded
+	}
+	if status.Code == MultipleError {
+		for _, subStatus := range status.GetSubStatus() {
+			if GetWriteLoadHint(subStatus) == WriteLoadOverloaded {
+				return WriteLoadOverloaded
+			}
+		}
+	}
+	return WriteLoadNormal
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apache/iotdb-client-go/rpc"
+)
+
+func TestGetWriteLoadHint(t *testing.T) {
+	message := "memory is full"
+	tests := []struct {
+		name   string
+		status *rpc.TSStatus
+		want   WriteLoadHint
+	}{
+		{
+			name:   "Nil",
+			status: nil,
+			want:   WriteLoadNormal,
+		}, {
+			name:   "SuccessStatus",
+			status: &rpc.TSStatus{Code: SuccessStatus},
+			want:   WriteLoadNormal,
+		}, {
+			name:   "WriteProcessReject",
+			status: &rpc.TSStatus{Code: WriteProcessReject, Message: &message},
+			want:   WriteLoadOverloaded,
+		}, {
+			name: "MultipleError",
+			status: &rpc.TSStatus{
+				Code: MultipleError,
+				SubStatus: []*rpc.TSStatus{
+					{Code: SuccessStatus},
+					{Code: WriteProcessReject, Message: &message},
+				},
+			},
+			want: WriteLoadOverloaded,
+		}, {
+			name:   "Other error",
+			status: &rpc.TSStatus{Code: InternalServerError, Message: &message},
+			want:   WriteLoadNormal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetWriteLoadHint(tt.status); got != tt.want {
+				t.Errorf("GetWriteLoadHint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySuccess_writeProcessReject(t *testing.T) {
+	message := "memory is full"
+	err := VerifySuccess(&rpc.TSStatus{Code: WriteProcessReject, Message: &message})
+	if !errors.Is(err, ErrWriteRejected) {
+		t.Errorf("VerifySuccess() error = %v, want %v", err, ErrWriteRejected)
+	}
+}